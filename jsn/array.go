@@ -0,0 +1,294 @@
+package jsn
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+)
+
+// HasDuplicates returns true if some element appears more than once in the array.
+// Elements are compared by value with Json.Equal, so objects and arrays are duplicates
+// when their content is deeply equal, and 1 is a duplicate of 1.0.
+// returns false if !(.IsValid)
+func (a Array) HasDuplicates() bool {
+	for i := range a.elements {
+		for k := i + 1; k < len(a.elements); k++ {
+			if equalValues(a.elements[i], a.elements[k]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Duplicates returns the elements that appear more than once in the array (compared by value,
+// as in HasDuplicates), each one only once, in the order of their first appearance.
+// Defaults to empty array if there are no duplicates or !(.IsValid)
+func (a Array) Duplicates() []Json {
+	dups := []Json{}
+	counted := make([]bool, len(a.elements))
+
+	for i := range a.elements {
+		if counted[i] {
+			continue
+		}
+
+		duplicated := false
+		for k := i + 1; k < len(a.elements); k++ {
+			if !counted[k] && equalValues(a.elements[i], a.elements[k]) {
+				counted[k] = true
+				duplicated = true
+			}
+		}
+		if duplicated {
+			dups = append(dups, Json{data: a.elements[i], exists: true})
+		}
+	}
+
+	return dups
+}
+
+// AllInRange checks that the numeric elements of the array are within [min, max].
+// returns whether all of them are, and the indexes of the elements that aren't.
+// Non-numeric elements are failures (their index is returned) unless skipNonNumeric is true,
+// in which case they're ignored.
+// returns false if !(.IsValid)
+func (a Array) AllInRange(min, max float64, skipNonNumeric bool) (bool, []int) {
+	failed := []int{}
+	if !a.IsValid {
+		return false, failed
+	}
+
+	for i, e := range a.elements {
+		f, ok := numberValue(e)
+		if !ok {
+			if !skipNonNumeric {
+				failed = append(failed, i)
+			}
+			continue
+		}
+		if f < min || f > max {
+			failed = append(failed, i)
+		}
+	}
+
+	return len(failed) == 0, failed
+}
+
+// MapField returns a copy of the array where the value under key, in every element that has it,
+// is replaced with f's result, e.g. to double a "price" field across all records.
+// f may return a Json or any json.Marshal-able value. If that value can't be marshaled,
+// the field is left unchanged.
+// Elements that don't have the key, and elements that aren't objects, are left unchanged.
+// returns an invalid Array if !(.IsValid)
+func (a Array) MapField(key string, f func(v Json) interface{}) Array {
+	if !a.IsValid {
+		return Array{}
+	}
+
+	mapped := deepCopy(a.elements).([]interface{})
+	for _, e := range mapped {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		v, exists := m[key]
+		if !exists {
+			continue
+		}
+
+		if value, err := normalize(f(Json{data: v, exists: true})); err == nil {
+			m[key] = value
+		}
+	}
+
+	return Array{mapped, true}
+}
+
+// FindBy returns the first element that is an object whose key deep-equals value
+// (compared like Json.Equal, so 42 matches 42.0), e.g. orders.FindBy("id", 42).
+// value can be a Json or any json.Marshal-able value. Elements that aren't objects are skipped.
+// returns an undefined Json and false if no element matches, or if !(.IsValid)
+func (a Array) FindBy(key string, value interface{}) (Json, bool) {
+	want, err := normalize(value)
+	if err != nil {
+		return Json{}, false
+	}
+
+	for _, e := range a.elements {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if v, exists := m[key]; exists && equalValues(v, want) {
+			return Json{data: e, exists: true}, true
+		}
+	}
+
+	return Json{}, false
+}
+
+// ElementKinds returns the Kind of each element, in order.
+// Defaults to empty array if !(.IsValid)
+func (a Array) ElementKinds() []Kind {
+	kinds := make([]Kind, len(a.elements))
+	for i, e := range a.elements {
+		kinds[i] = Json{data: e, exists: true}.Type()
+	}
+
+	return kinds
+}
+
+// IsHomogeneous returns the Kind shared by all the elements, and whether they all share it,
+// e.g. to check the array can be extracted into a typed slice.
+// An empty array is homogeneous, with KindInvalid as its Kind (as is an Array that's !(.IsValid)).
+// returns KindInvalid and false for mixed arrays.
+func (a Array) IsHomogeneous() (Kind, bool) {
+	kinds := a.ElementKinds()
+	if len(kinds) == 0 {
+		return KindInvalid, true
+	}
+
+	for _, k := range kinds[1:] {
+		if k != kinds[0] {
+			return KindInvalid, false
+		}
+	}
+
+	return kinds[0], true
+}
+
+// Flatten returns a copy of the array where nested arrays are replaced by their elements,
+// up to depth levels of nesting, like JavaScript's Array.prototype.flat:
+// [[1, 2], [3, [4]]] gives [1, 2, 3, [4]] with depth 1, and [1, 2, 3, 4] with depth 2.
+// A negative depth flattens fully, and 0 just copies the array.
+// Elements that aren't arrays are kept as they are.
+// returns an invalid Array if !(.IsValid)
+func (a Array) Flatten(depth int) Array {
+	if !a.IsValid {
+		return Array{}
+	}
+
+	return Array{flattenArray([]interface{}{}, a.elements, depth), true}
+}
+
+func flattenArray(flat, elements []interface{}, depth int) []interface{} {
+	for _, e := range elements {
+		if nested, ok := e.([]interface{}); ok && depth != 0 {
+			flat = flattenArray(flat, nested, depth-1)
+		} else {
+			flat = append(flat, deepCopy(e))
+		}
+	}
+
+	return flat
+}
+
+// AsSet converts an array of scalars into a set, for O(1) membership checks.
+// Every element is keyed by its compact canonical JSON text (as in Snapshot), so strings are
+// quoted and numbers are formatted independently of how they were parsed: "a" is keyed `"a"`,
+// 1 and 1.0 are both keyed `1`, and true is keyed `true`, distinct from the string "true".
+// returns nil and false if this isn't an array, or if an element is an object or an array.
+func (j Json) AsSet() (map[string]struct{}, bool) {
+	a, ok := j.asArray()
+	if !ok {
+		return nil, false
+	}
+
+	set := make(map[string]struct{}, len(a))
+	for _, e := range a {
+		key, ok := setKey(e)
+		if !ok {
+			return nil, false
+		}
+		set[key] = struct{}{}
+	}
+
+	return set, true
+}
+
+// setKey returns the canonical JSON text of a scalar value
+func setKey(data interface{}) (string, bool) {
+	switch data.(type) {
+	case map[string]interface{}, []interface{}:
+		return "", false
+	}
+
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, data, "", "", 0); err != nil {
+		return "", false
+	}
+
+	return buf.String(), true
+}
+
+// Has returns true if some element deep-equals value (compared like Json.Equal, so 1 matches 1.0).
+// value can be a Json or any json.Marshal-able value.
+// To check many values against the same array, build a set with Json.AsSet instead.
+// returns false if !(.IsValid)
+func (a Array) Has(value interface{}) bool {
+	want, err := normalize(value)
+	if err != nil {
+		return false
+	}
+
+	for _, e := range a.elements {
+		if equalValues(e, want) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SampleMode selects how Sample picks elements
+type SampleMode int
+
+// The sampling strategies of Sample
+const (
+	// SampleEvenly picks evenly-spaced elements, always including the first and the last
+	SampleEvenly SampleMode = iota
+	// SampleRandomly picks elements at random (with math/rand), keeping their relative order
+	SampleRandomly
+)
+
+// Sample returns a copy of up to n elements of the array, for previewing huge arrays.
+// By default the elements are evenly spaced: sampling 3 out of 10 elements gives those at
+// indexes 0, 4 and 9. Passing SampleRandomly picks n distinct elements at random instead.
+// Either way, the elements are in the order they appear in the array.
+// If n is at least the array's length, the whole array is copied; if n <= 0, it's empty.
+// returns an invalid Array if !(.IsValid)
+func (a Array) Sample(n int, mode ...SampleMode) Array {
+	if !a.IsValid {
+		return Array{}
+	}
+
+	var indexes []int
+	switch {
+	case n >= len(a.elements):
+		indexes = make([]int, len(a.elements))
+		for i := range indexes {
+			indexes[i] = i
+		}
+	case n <= 0:
+		indexes = []int{}
+	case len(mode) > 0 && mode[0] == SampleRandomly:
+		indexes = rand.Perm(len(a.elements))[:n]
+		sort.Ints(indexes)
+	case n == 1:
+		indexes = []int{0}
+	default:
+		indexes = make([]int, n)
+		for i := range indexes {
+			indexes[i] = i * (len(a.elements) - 1) / (n - 1)
+		}
+	}
+
+	sample := make([]interface{}, len(indexes))
+	for i, index := range indexes {
+		sample[i] = deepCopy(a.elements[index])
+	}
+
+	return Array{sample, true}
+}
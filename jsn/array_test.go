@@ -0,0 +1,291 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicates(t *testing.T) {
+	j, err := NewJson(`{
+		"ids": [3, 1, 2, 1.0, 3, 1],
+		"unique": ["a", "b", 1, "1", null, false],
+		"objects": [{"id": 1, "tags": ["x"]}, {"id": 2}, {"tags": ["x"], "id": 1}],
+		"empty": []
+	}`)
+	require.NoError(t, err)
+
+	ids := j.K("ids").Array()
+	assert.True(t, ids.HasDuplicates())
+	dups := ids.Duplicates()
+	require.Len(t, dups, 2)
+	assert.Equal(t, 3, dups[0].Int().Value)
+	assert.Equal(t, 1, dups[1].Int().Value)
+
+	assert.False(t, j.K("unique").Array().HasDuplicates())
+	assert.Equal(t, []Json{}, j.K("unique").Array().Duplicates())
+
+	objects := j.K("objects").Array()
+	assert.True(t, objects.HasDuplicates())
+	dups = objects.Duplicates()
+	require.Len(t, dups, 1)
+	assert.Equal(t, `{"id":1,"tags":["x"]}`, dups[0].Stringify())
+
+	assert.False(t, j.K("empty").Array().HasDuplicates())
+	assert.False(t, j.K("no").Array().HasDuplicates())
+	assert.Equal(t, []Json{}, j.K("no").Array().Duplicates())
+}
+
+func TestAllInRange(t *testing.T) {
+	j, err := NewJson(`{
+		"ok": [0, 1.5, 10, 5],
+		"out": [0, -1, 11, 10.01, 3],
+		"mixed": [1, "2", null, 3, 100],
+		"empty": []
+	}`)
+	require.NoError(t, err)
+
+	ok, failed := j.K("ok").Array().AllInRange(0, 10, false)
+	assert.True(t, ok)
+	assert.Equal(t, []int{}, failed)
+
+	ok, failed = j.K("out").Array().AllInRange(0, 10, false)
+	assert.False(t, ok)
+	assert.Equal(t, []int{1, 2, 3}, failed)
+
+	ok, failed = j.K("mixed").Array().AllInRange(0, 10, false)
+	assert.False(t, ok)
+	assert.Equal(t, []int{1, 2, 4}, failed)
+
+	ok, failed = j.K("mixed").Array().AllInRange(0, 10, true)
+	assert.False(t, ok)
+	assert.Equal(t, []int{4}, failed)
+
+	ok, failed = j.K("mixed").Array().AllInRange(0, 100, true)
+	assert.True(t, ok)
+	assert.Equal(t, []int{}, failed)
+
+	ok, _ = j.K("empty").Array().AllInRange(0, 1, false)
+	assert.True(t, ok)
+
+	ok, failed = j.K("no").Array().AllInRange(0, 1, false)
+	assert.False(t, ok)
+	assert.Equal(t, []int{}, failed)
+}
+
+func TestMapField(t *testing.T) {
+	j, err := NewJson(`[
+		{"sku": "a", "price": 10},
+		{"sku": "b", "price": 2.5},
+		{"sku": "c"},
+		"not an object",
+		{"sku": "d", "price": 1}
+	]`)
+	require.NoError(t, err)
+
+	doubled := j.Array().MapField("price", func(v Json) interface{} {
+		return v.Float64().Value * 2
+	})
+	require.True(t, doubled.IsValid)
+
+	out, err := NewJson(doubled.Elements())
+	require.NoError(t, err)
+	expected, err := NewJson(`[
+		{"sku": "a", "price": 20},
+		{"sku": "b", "price": 5},
+		{"sku": "c"},
+		"not an object",
+		{"sku": "d", "price": 2}
+	]`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(out), out.Stringify())
+
+	assert.Equal(t, 10, j.I(0).K("price").Int().Value, "original must not be modified")
+
+	wrapped := j.Array().MapField("sku", func(v Json) interface{} {
+		if v.String().Value == "b" {
+			return make(chan int)
+		}
+		return Map{"id": v}
+	})
+	assert.Equal(t, "a", wrapped.Elements()[0].K("sku").K("id").String().Value)
+	assert.Equal(t, "b", wrapped.Elements()[1].K("sku").String().Value)
+
+	assert.False(t, j.K("no").Array().MapField("x", func(v Json) interface{} { return v }).IsValid)
+}
+
+func TestElementKinds(t *testing.T) {
+	j, err := NewJson(`{"nums": [1, 2.5, 3], "mixed": [1, "a", null, true, [], {}], "empty": [], "nested": [[1], [2]]}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Kind{KindNumber, KindNumber, KindNumber}, j.K("nums").Array().ElementKinds())
+	assert.Equal(t, []Kind{KindNumber, KindString, KindNull, KindBool, KindArray, KindObject}, j.K("mixed").Array().ElementKinds())
+	assert.Equal(t, []Kind{}, j.K("empty").Array().ElementKinds())
+	assert.Equal(t, []Kind{}, j.K("no").Array().ElementKinds())
+
+	kind, ok := j.K("nums").Array().IsHomogeneous()
+	assert.True(t, ok)
+	assert.Equal(t, KindNumber, kind)
+
+	kind, ok = j.K("nested").Array().IsHomogeneous()
+	assert.True(t, ok)
+	assert.Equal(t, KindArray, kind)
+
+	kind, ok = j.K("mixed").Array().IsHomogeneous()
+	assert.False(t, ok)
+	assert.Equal(t, KindInvalid, kind)
+
+	kind, ok = j.K("empty").Array().IsHomogeneous()
+	assert.True(t, ok)
+	assert.Equal(t, KindInvalid, kind)
+}
+
+func TestFindBy(t *testing.T) {
+	j, err := NewJson(`[
+		"not an object",
+		{"id": 41, "status": "open"},
+		{"id": 42, "status": "paid", "tags": ["a"]},
+		{"id": 42, "status": "dup"},
+		{"status": null}
+	]`)
+	require.NoError(t, err)
+	orders := j.Array()
+
+	order, ok := orders.FindBy("id", 42)
+	assert.True(t, ok)
+	assert.Equal(t, "paid", order.K("status").String().Value)
+
+	order, ok = orders.FindBy("tags", []string{"a"})
+	assert.True(t, ok)
+	assert.Equal(t, 42, order.K("id").Int().Value)
+
+	order, ok = orders.FindBy("status", nil)
+	assert.True(t, ok)
+	assert.True(t, order.K("id").Undefined())
+
+	order, ok = orders.FindBy("id", j.I(1).K("id"))
+	assert.True(t, ok)
+	assert.Equal(t, "open", order.K("status").String().Value)
+
+	order, ok = orders.FindBy("id", 7)
+	assert.False(t, ok)
+	assert.True(t, order.Undefined())
+
+	_, ok = orders.FindBy("id", "42")
+	assert.False(t, ok)
+	_, ok = orders.FindBy("missing", nil)
+	assert.False(t, ok)
+	_, ok = j.K("no").Array().FindBy("id", 42)
+	assert.False(t, ok)
+}
+
+func TestArrayFlatten(t *testing.T) {
+	j, err := NewJson(`[[1, 2], [3, [4, [5]]], 6, {"a": [7]}, []]`)
+	require.NoError(t, err)
+	a := j.Array()
+
+	stringify := func(a Array) string {
+		out, err := NewJson(a.Elements())
+		require.NoError(t, err)
+		return out.Stringify()
+	}
+
+	assert.Equal(t, `[[1,2],[3,[4,[5]]],6,{"a":[7]},[]]`, stringify(a.Flatten(0)))
+	assert.Equal(t, `[1,2,3,[4,[5]],6,{"a":[7]}]`, stringify(a.Flatten(1)))
+	assert.Equal(t, `[1,2,3,4,[5],6,{"a":[7]}]`, stringify(a.Flatten(2)))
+	assert.Equal(t, `[1,2,3,4,5,6,{"a":[7]}]`, stringify(a.Flatten(-1)))
+	assert.Equal(t, `[]`, stringify(j.I(4).Array().Flatten(-1)))
+	assert.Equal(t, `[[1,2],[3,[4,[5]]],6,{"a":[7]},[]]`, j.Stringify(), "original must not be modified")
+
+	assert.False(t, j.I(2).Array().Flatten(1).IsValid)
+}
+
+func TestAsSet(t *testing.T) {
+	j, err := NewJson(`{"tags": ["a", "b", "a", 1, 2.0, true, "true", null], "mixed": [1, [2]], "empty": [], "obj": {"a": 1}}`)
+	require.NoError(t, err)
+
+	set, ok := j.K("tags").AsSet()
+	require.True(t, ok)
+	assert.Len(t, set, 7)
+	for _, key := range []string{`"a"`, `"b"`, `1`, `2`, `true`, `"true"`, `null`} {
+		_, hit := set[key]
+		assert.True(t, hit, key)
+	}
+	for _, key := range []string{`a`, `"c"`, `2.0`, `false`} {
+		_, hit := set[key]
+		assert.False(t, hit, key)
+	}
+
+	set, ok = j.K("empty").AsSet()
+	assert.True(t, ok)
+	assert.Empty(t, set)
+
+	for _, key := range []string{"mixed", "obj", "missing"} {
+		set, ok = j.K(key).AsSet()
+		assert.False(t, ok, key)
+		assert.Nil(t, set, key)
+	}
+}
+
+func TestArrayHas(t *testing.T) {
+	j, err := NewJson(`["a", 1, 2.5, null, {"id": 7}, [1]]`)
+	require.NoError(t, err)
+	a := j.Array()
+
+	assert.True(t, a.Has("a"))
+	assert.True(t, a.Has(1.0))
+	assert.True(t, a.Has(2.5))
+	assert.True(t, a.Has(nil))
+	assert.True(t, a.Has(map[string]int{"id": 7}))
+	assert.True(t, a.Has(j.I(5)))
+
+	assert.False(t, a.Has("b"))
+	assert.False(t, a.Has("1"))
+	assert.False(t, a.Has(map[string]int{"id": 8}))
+	assert.False(t, a.Has(func() {}))
+	assert.False(t, Json{}.Array().Has("a"))
+}
+
+func TestArraySample(t *testing.T) {
+	j, err := NewJson(`[0, 1, 2, 3, 4, 5, 6, 7, 8, 9]`)
+	require.NoError(t, err)
+	a := j.Array()
+
+	ints := func(a Array) []int {
+		values := []int{}
+		for _, e := range a.Elements() {
+			values = append(values, e.Int().Value)
+		}
+		return values
+	}
+
+	assert.Equal(t, []int{0, 4, 9}, ints(a.Sample(3)))
+	assert.Equal(t, []int{0, 9}, ints(a.Sample(2)))
+	assert.Equal(t, []int{0, 2, 4, 6, 9}, ints(a.Sample(5, SampleEvenly)))
+	assert.Equal(t, []int{0}, ints(a.Sample(1)))
+	assert.Equal(t, []int{}, ints(a.Sample(0)))
+	assert.Equal(t, []int{}, ints(a.Sample(-1)))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, ints(a.Sample(10)))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, ints(a.Sample(100)))
+
+	for i := 0; i < 20; i++ {
+		random := ints(a.Sample(4, SampleRandomly))
+		assert.Len(t, random, 4)
+		for k := 1; k < len(random); k++ {
+			assert.True(t, random[k-1] < random[k], "distinct and in order: %v", random)
+		}
+	}
+
+	assert.False(t, Json{}.Array().Sample(3).IsValid)
+}
+
+func TestArraySampleCopies(t *testing.T) {
+	j, err := NewJson(`[{"a": 1}, {"a": 2}]`)
+	require.NoError(t, err)
+
+	sample := j.Array().Sample(1)
+	first := sample.Elements()[0]
+	require.NoError(t, first.Set("a", 10))
+	assert.Equal(t, `[{"a":1},{"a":2}]`, j.Stringify())
+}
@@ -0,0 +1,50 @@
+package jsn
+
+// As decodes the value into a T, e.g. As[Config](j.K("config")), and returns whether it's valid.
+// Scalar types (string, bool, int, int64, uint, uint64 and float64) use the matching accessor,
+// e.g. As[int] is like Int(), without a marshal round-trip. Other types are decoded like
+// Unmarshal, and are valid if that succeeds.
+// Like the accessors, null and undefined values are never valid, and give the zero value of T.
+func As[T any](j Json) (T, bool) {
+	var zero T
+	if !j.exists || j.data == nil {
+		return zero, false
+	}
+
+	var v interface{}
+	var valid bool
+	switch any(zero).(type) {
+	case string:
+		s := j.String()
+		v, valid = s.Value, s.IsValid
+	case bool:
+		b := j.Bool()
+		v, valid = b.Value, b.IsValid
+	case int:
+		i := j.Int()
+		v, valid = i.Value, i.IsValid
+	case int64:
+		i := j.Int64()
+		v, valid = i.Value, i.IsValid
+	case uint:
+		u := j.Uint()
+		v, valid = u.Value, u.IsValid
+	case uint64:
+		u := j.Uint64()
+		v, valid = u.Value, u.IsValid
+	case float64:
+		f := j.Float64()
+		v, valid = f.Value, f.IsValid
+	default:
+		var target T
+		if err := j.Unmarshal(&target); err != nil {
+			return zero, false
+		}
+		return target, true
+	}
+
+	if !valid {
+		return zero, false
+	}
+	return v.(T), true
+}
@@ -0,0 +1,129 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type asConfig struct {
+	Name    string         `json:"name"`
+	Port    int            `json:"port"`
+	Tags    []string       `json:"tags"`
+	Limits  map[string]int `json:"limits"`
+	Enabled bool           `json:"enabled"`
+}
+
+func TestAsStruct(t *testing.T) {
+	j, err := NewJson(`{"config": {"name": "api", "port": 8080, "tags": ["a", "b"], "limits": {"rps": 100}, "enabled": true, "extra": 1}}`)
+	require.NoError(t, err)
+
+	config, ok := As[asConfig](j.K("config"))
+	require.True(t, ok)
+	assert.Equal(t, asConfig{Name: "api", Port: 8080, Tags: []string{"a", "b"}, Limits: map[string]int{"rps": 100}, Enabled: true}, config)
+
+	ptr, ok := As[*asConfig](j.K("config"))
+	require.True(t, ok)
+	assert.Equal(t, "api", ptr.Name)
+
+	config, ok = As[asConfig](j.K("config").K("tags"))
+	assert.False(t, ok)
+	assert.Equal(t, asConfig{}, config)
+}
+
+func TestAsSlicesAndMaps(t *testing.T) {
+	j, err := NewJson(`{"tags": ["a", "b"], "ports": [80, 443], "limits": {"rps": 100, "burst": 20}, "mixed": [1, "x"]}`)
+	require.NoError(t, err)
+
+	tags, ok := As[[]string](j.K("tags"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a", "b"}, tags)
+
+	ports, ok := As[[]int](j.K("ports"))
+	assert.True(t, ok)
+	assert.Equal(t, []int{80, 443}, ports)
+
+	limits, ok := As[map[string]int](j.K("limits"))
+	assert.True(t, ok)
+	assert.Equal(t, map[string]int{"rps": 100, "burst": 20}, limits)
+
+	mixed, ok := As[[]interface{}](j.K("mixed"))
+	assert.True(t, ok)
+	assert.Len(t, mixed, 2)
+
+	ports, ok = As[[]int](j.K("mixed"))
+	assert.False(t, ok)
+	assert.Nil(t, ports)
+
+	limits, ok = As[map[string]int](j.K("tags"))
+	assert.False(t, ok)
+	assert.Nil(t, limits)
+}
+
+func TestAsScalars(t *testing.T) {
+	j, err := NewJson(`{"s": "text", "b": true, "i": 42, "big": 9007199254740993, "u": 18446744073709551615, "f": 2.5, "n": null}`)
+	require.NoError(t, err)
+
+	s, ok := As[string](j.K("s"))
+	assert.True(t, ok)
+	assert.Equal(t, "text", s)
+
+	b, ok := As[bool](j.K("b"))
+	assert.True(t, ok)
+	assert.True(t, b)
+
+	i, ok := As[int](j.K("i"))
+	assert.True(t, ok)
+	assert.Equal(t, 42, i)
+
+	i64, ok := As[int64](j.K("big"))
+	assert.True(t, ok)
+	assert.Equal(t, int64(9007199254740993), i64)
+
+	u, ok := As[uint](j.K("i"))
+	assert.True(t, ok)
+	assert.Equal(t, uint(42), u)
+
+	u64, ok := As[uint64](j.K("u"))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(18446744073709551615), u64)
+
+	f, ok := As[float64](j.K("f"))
+	assert.True(t, ok)
+	assert.Equal(t, 2.5, f)
+
+	// truncated like Int()
+	i, ok = As[int](j.K("f"))
+	assert.True(t, ok)
+	assert.Equal(t, 2, i)
+
+	s, ok = As[string](j.K("i"))
+	assert.False(t, ok)
+	assert.Equal(t, "", s)
+
+	// other scalar types are decoded like Unmarshal
+	i32, ok := As[int32](j.K("i"))
+	assert.True(t, ok)
+	assert.Equal(t, int32(42), i32)
+	_, ok = As[int32](j.K("s"))
+	assert.False(t, ok)
+}
+
+func TestAsNullAndUndefined(t *testing.T) {
+	j, err := NewJson(`{"n": null}`)
+	require.NoError(t, err)
+
+	for _, v := range []Json{j.K("n"), j.K("missing")} {
+		_, ok := As[string](v)
+		assert.False(t, ok)
+		_, ok = As[asConfig](v)
+		assert.False(t, ok)
+		p, ok := As[*asConfig](v)
+		assert.False(t, ok)
+		assert.Nil(t, p)
+		m, ok := As[map[string]int](v)
+		assert.False(t, ok)
+		assert.Nil(t, m)
+	}
+}
@@ -0,0 +1,221 @@
+package jsn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// writeCanonical encodes data with object keys sorted and numbers formatted by formatNumber.
+// with an empty indent the output is compact
+func writeCanonical(buf *bytes.Buffer, data interface{}, prefix, indent string, depth int) error {
+	newline := func(depth int) {
+		if indent != "" {
+			buf.WriteByte('\n')
+			buf.WriteString(prefix)
+			buf.WriteString(strings.Repeat(indent, depth))
+		}
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+
+		buf.WriteByte('{')
+		for i, k := range sortedKeys(v) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(depth + 1)
+
+			key, err := marshalNoEscape(k)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if indent != "" {
+				buf.WriteByte(' ')
+			}
+
+			if err := writeCanonical(buf, v[k], prefix, indent, depth+1); err != nil {
+				return err
+			}
+		}
+		newline(depth)
+		buf.WriteByte('}')
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+
+		buf.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(depth + 1)
+
+			if err := writeCanonical(buf, e, prefix, indent, depth+1); err != nil {
+				return err
+			}
+		}
+		newline(depth)
+		buf.WriteByte(']')
+	case float64, json.Number:
+		s, err := formatNumber(v)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(s)
+	default:
+		b, err := marshalNoEscape(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+
+	return nil
+}
+
+// marshalNoEscape is json.Marshal without the escaping of <, > and &
+func marshalNoEscape(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// formatNumber gives a number a representation that depends only on its exact decimal value,
+// the one equalNumbers compares, so numbers are written identically if and only if they're equal.
+// It's the way encoding/json writes a float64, but with all the digits of the number:
+// plain notation from 1e-6 up to 1e21, exponent notation outside (1e-7, 1e+21).
+// so 1, 1.0 and 1e0 are all written as 1, and 9007199254740993.0 as 9007199254740993
+func formatNumber(n interface{}) (string, error) {
+	d, ok := toDecimal(n)
+	if !ok {
+		return "", fmt.Errorf("invalid number %v", n)
+	}
+	if d.digits == "" {
+		return "0", nil
+	}
+
+	var b strings.Builder
+	if d.neg {
+		b.WriteByte('-')
+	}
+
+	switch {
+	case d.hugePoint != "" || d.point < -5 || d.point > 21:
+		b.WriteString(d.digits[:1])
+		if len(d.digits) > 1 {
+			b.WriteString("." + d.digits[1:])
+		}
+		b.WriteString("e" + d.exponent())
+	case d.point <= 0:
+		b.WriteString("0." + strings.Repeat("0", -d.point) + d.digits)
+	case d.point >= len(d.digits):
+		b.WriteString(d.digits + strings.Repeat("0", d.point-len(d.digits)))
+	default:
+		b.WriteString(d.digits[:d.point] + "." + d.digits[d.point:])
+	}
+
+	return b.String(), nil
+}
+
+// Snapshot returns an indented JSON with sorted keys and stable number formatting,
+// meant for golden-file / snapshot tests: output is byte-identical for equal documents
+// however they were parsed (float64 or json.Number).
+// Numbers are written by exact value, like encoding/json writes a float64 but keeping all
+// their digits: 1.0 and 1e0 become 1, and the exponent is only used below 1e-6 or from 1e21
+// (1e-07 as 1e-7).
+// Strings are not HTML-escaped and indentation is two spaces.
+// Returns an empty string if the value can't be encoded.
+func (j Json) Snapshot() string {
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, j.data, "", "  ", 0); err != nil {
+		return ""
+	}
+
+	return buf.String()
+}
+
+// Canonical returns the canonical form of the value, to compute content hashes that are
+// stable across processes: compact JSON (no insignificant whitespace) with object keys
+// sorted recursively, and the number formatting of Snapshot: numbers are written by exact
+// value, so numbers that are Equal are written identically (1, 1.0 and 1e0 all as 1), and
+// large integers keep all their digits. A float64 has the value of its shortest decimal form.
+// Strings are not HTML-escaped. An undefined Json gives null.
+// Returns an error if the value can't be encoded, e.g. a float64 that is NaN or infinite.
+func (j Json) Canonical() (string, error) {
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, j.data, "", "", 0); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// HashWith writes the canonical encoding of the value (see Canonical) to h, and returns
+// the resulting sum, to fingerprint documents with any hash algorithm. Equal documents
+// (per Equal) get the same sum whatever their key order, whitespace or number representation.
+// h isn't reset first. Returns nil if the value can't be encoded.
+func (j Json) HashWith(h hash.Hash) []byte {
+	canonical, err := j.Canonical()
+	if err != nil {
+		return nil
+	}
+
+	h.Write([]byte(canonical))
+	return h.Sum(nil)
+}
+
+// Hash returns the hex SHA-256 of the canonical encoding of the value (see HashWith),
+// to dedupe and cache documents by value.
+// Returns an empty string if the value can't be encoded.
+func (j Json) Hash() string {
+	sum := j.HashWith(sha256.New())
+	if sum == nil {
+		return ""
+	}
+
+	return hex.EncodeToString(sum)
+}
+
+// ETag returns a strong HTTP entity tag for the value: its Hash, the hex SHA-256 of its
+// compact canonical encoding, in double quotes, e.g. "5e88...a1". Equal documents get
+// the same ETag whatever their key order or number representation.
+// Returns an empty string if the value can't be encoded.
+func (j Json) ETag() string {
+	sum := j.Hash()
+	if sum == "" {
+		return ""
+	}
+
+	return `"` + sum + `"`
+}
+
+// WeakETag returns the ETag prefixed with W/, marking it as a weak validator
+// (semantically equivalent, not byte-identical, responses), e.g. W/"5e88...a1".
+// Returns an empty string if the value can't be encoded.
+func (j Json) WeakETag() string {
+	etag := j.ETag()
+	if etag == "" {
+		return ""
+	}
+
+	return "W/" + etag
+}
@@ -0,0 +1,205 @@
+package jsn
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	doc := `{"z": [1.0, 2.50, 1e3, 1e-7, -0], "a": {"s": "x<y", "e": {}, "l": [], "n": null, "b": true}}`
+
+	var data interface{}
+	require.NoError(t, json.Unmarshal([]byte(doc), &data))
+	withFloats := Json{data: data, exists: true}
+	withNumbers := decodeUseNumber(t, doc)
+	reordered := decodeUseNumber(t, `{"a": {"b": true, "n": null, "l": [], "e": {}, "s": "x<y"}, "z": [1, 2.5, 1000, 0.0000001, 0]}`)
+
+	expected := `{
+  "a": {
+    "b": true,
+    "e": {},
+    "l": [],
+    "n": null,
+    "s": "x<y"
+  },
+  "z": [
+    1,
+    2.5,
+    1000,
+    1e-7,
+    0
+  ]
+}`
+	assert.Equal(t, expected, withFloats.Snapshot())
+	assert.Equal(t, expected, withNumbers.Snapshot())
+	assert.Equal(t, expected, reordered.Snapshot())
+
+	assert.Equal(t, "12345678901234567", decodeUseNumber(t, "12345678901234567").Snapshot())
+	assert.Equal(t, `"lonely"`, Json{data: "lonely", exists: true}.Snapshot())
+	assert.Equal(t, "null", Json{}.Snapshot())
+	assert.Equal(t, "", Json{data: map[string]interface{}{"c": make(chan int)}, exists: true}.Snapshot())
+}
+
+func TestCanonical(t *testing.T) {
+	doc := `{"z": [1.0, 2.50, 1e3, 1e-7, -0], "a": {"s": "x<y", "e": {}, "l": [], "n": null, "b": true}}`
+
+	var data interface{}
+	require.NoError(t, json.Unmarshal([]byte(doc), &data))
+	withFloats := Json{data: data, exists: true}
+	withNumbers := decodeUseNumber(t, doc)
+	reordered := decodeUseNumber(t, `{ "a" : {"b": true, "n": null, "l": [], "e": {}, "s": "x<y"},
+		"z": [1, 2.5, 1000, 0.0000001, 0] }`)
+
+	expected := `{"a":{"b":true,"e":{},"l":[],"n":null,"s":"x<y"},"z":[1,2.5,1000,1e-7,0]}`
+	for _, j := range []Json{withFloats, withNumbers, reordered} {
+		canonical, err := j.Canonical()
+		require.NoError(t, err)
+		assert.Equal(t, expected, canonical)
+	}
+
+	canonical, err := decodeUseNumber(t, "12345678901234567").Canonical()
+	require.NoError(t, err)
+	assert.Equal(t, "12345678901234567", canonical)
+
+	canonical, err = Json{}.Canonical()
+	require.NoError(t, err)
+	assert.Equal(t, "null", canonical)
+
+	_, err = Json{data: map[string]interface{}{"c": make(chan int)}, exists: true}.Canonical()
+	assert.Error(t, err)
+}
+
+func TestHash(t *testing.T) {
+	a, err := NewJson(`{"id": 1, "tags": ["x", "y"], "meta": {"b": 2.0, "a": null, "big": 12345678901234567}}`)
+	require.NoError(t, err)
+	b, err := NewJson(`{
+		"meta": {"big": 12345678901234567, "a": null, "b": 2},
+		"tags": ["x", "y"],
+		"id": 1e0
+	}`)
+	require.NoError(t, err)
+	require.True(t, a.Equal(b))
+	assert.Equal(t, a.Hash(), b.Hash())
+	assert.Equal(t, a.HashWith(sha1.New()), b.HashWith(sha1.New()))
+
+	// float64 numbers, as decoded without UseNumber
+	var data interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"n": 2.50, "m": [1e3, -0]}`), &data))
+	withFloats := Json{data: data, exists: true}
+	withNumbers, err := NewJson(`{"m": [1000, 0], "n": 2.5}`)
+	require.NoError(t, err)
+	require.True(t, withFloats.Equal(withNumbers))
+	assert.Equal(t, withFloats.Hash(), withNumbers.Hash())
+
+	assert.Regexp(t, `^[0-9a-f]{64}$`, a.Hash())
+	assert.Len(t, a.HashWith(sha1.New()), sha1.Size)
+
+	sum := sha256.Sum256([]byte(`{"id":1,"meta":{"a":null,"b":2,"big":12345678901234567},"tags":["x","y"]}`))
+	assert.Equal(t, hex.EncodeToString(sum[:]), a.Hash())
+
+	different, err := NewJson(`{"id": 1, "tags": ["y", "x"], "meta": {"b": 2, "a": null, "big": 12345678901234567}}`)
+	require.NoError(t, err)
+	assert.NotEqual(t, a.Hash(), different.Hash())
+
+	unencodable := Json{data: map[string]interface{}{"c": make(chan int)}, exists: true}
+	assert.Equal(t, "", unencodable.Hash())
+	assert.Nil(t, unencodable.HashWith(sha1.New()))
+}
+
+func TestHashLargeIntegers(t *testing.T) {
+	plain, err := NewJson(`{"id": 9007199254740993}`)
+	require.NoError(t, err)
+	decimal, err := NewJson(`{"id": 9007199254740993.0}`)
+	require.NoError(t, err)
+	exponent, err := NewJson(`{"id": 9.007199254740993e15}`)
+	require.NoError(t, err)
+	rounded, err := NewJson(`{"id": 9007199254740992}`)
+	require.NoError(t, err)
+
+	for _, other := range []Json{decimal, exponent} {
+		require.True(t, plain.Equal(other))
+		assert.Equal(t, plain.Hash(), other.Hash())
+		assert.Equal(t, plain.ETag(), other.ETag())
+	}
+	require.False(t, decimal.Equal(rounded))
+	assert.NotEqual(t, decimal.Hash(), rounded.Hash())
+
+	canonical, err := decimal.Canonical()
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":9007199254740993}`, canonical)
+}
+
+func TestHashHugeExponents(t *testing.T) {
+	j, err := NewJson(`{"big": 1e99999999999999999999, "small": -1.50E-99999999999999999999}`)
+	require.NoError(t, err)
+	same, err := NewJson(`{"big": 10e99999999999999999998, "small": -15e-100000000000000000000}`)
+	require.NoError(t, err)
+
+	canonical, err := j.Canonical()
+	require.NoError(t, err)
+	assert.Equal(t, `{"big":1e+99999999999999999999,"small":-1.5e-99999999999999999999}`, canonical)
+	assert.NotEmpty(t, j.Hash())
+	assert.NotEmpty(t, j.ETag())
+	assert.Equal(t, j.Hash(), same.Hash())
+	assert.Equal(t, j.ETag(), same.ETag())
+}
+
+func TestFormatNumber(t *testing.T) {
+	// float64s are written like encoding/json writes them
+	for _, f := range []float64{0, 1, -1, 2.5, 1e3, 123456.789, 1e20, 1e21, 1.5e300, 1e-6, 1e-7, -2.5e-10, 0.1, 1 / 3.0} {
+		expected, err := json.Marshal(f)
+		require.NoError(t, err)
+		s, err := formatNumber(f)
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), s, "%v", f)
+	}
+
+	tests := map[string]string{
+		"-0":                       "0",
+		"0.000":                    "0",
+		"1.0":                      "1",
+		"1e0":                      "1",
+		"12345678901234567890":     "12345678901234567890",
+		"1.00000000000000000001":   "1.00000000000000000001",
+		"1000e18":                  "1e+21",
+		"0.0000001":                "1e-7",
+		"-12.50e-8":                "-1.25e-7",
+		"0.00000125":               "0.00000125",
+		"10e1073741823":            "1e+1073741824",
+		"0.1e-1073741823":          "1e-1073741824",
+		"25e-99999999999999999999": "2.5e-99999999999999999998",
+	}
+	for number, expected := range tests {
+		s, err := formatNumber(json.Number(number))
+		require.NoError(t, err)
+		assert.Equal(t, expected, s, number)
+	}
+
+	_, err := formatNumber(json.Number("1x"))
+	assert.Error(t, err)
+}
+
+func TestETag(t *testing.T) {
+	a, err := NewJson(`{"id": 1, "tags": ["x", "y"], "meta": {"b": 2.0, "a": null}}`)
+	require.NoError(t, err)
+	b := decodeUseNumber(t, `{"meta": {"a": null, "b": 2}, "tags": ["x", "y"], "id": 1.0}`)
+
+	etag := a.ETag()
+	assert.Regexp(t, `^"[0-9a-f]{64}"$`, etag)
+	assert.Equal(t, etag, b.ETag())
+	assert.Equal(t, "W/"+etag, a.WeakETag())
+	assert.Equal(t, "W/"+etag, b.WeakETag())
+
+	reversed := decodeUseNumber(t, `{"id": 1, "tags": ["y", "x"], "meta": {"b": 2, "a": null}}`)
+	assert.NotEqual(t, etag, reversed.ETag())
+
+	unencodable := Json{data: map[string]interface{}{"c": make(chan int)}, exists: true}
+	assert.Equal(t, "", unencodable.ETag())
+	assert.Equal(t, "", unencodable.WeakETag())
+}
@@ -0,0 +1,64 @@
+package jsn
+
+import "encoding/json"
+
+// deepCopy copies the maps and slices of a decoded JSON value, so the copy shares no state with the original
+func deepCopy(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = deepCopy(e)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, e := range v {
+			a[i] = deepCopy(e)
+		}
+		return a
+	default:
+		return v
+	}
+}
+
+// Clone returns a deep copy of this Json: the clone shares no map or slice with the original,
+// so modifying either one in place (e.g. with Set) doesn't affect the other.
+func (j Json) Clone() Json {
+	return Json{data: deepCopy(j.data), exists: j.exists, obs: j.obs}
+}
+
+// CopyInto copies the entries of this object into an existing Go map, without the
+// marshal/unmarshal round-trip of Unmarshal. Keys already in the map are overwritten,
+// other keys are left as they are. The map is allocated if *target is nil.
+// Values are deep-copied, so the map and this Json don't share any nested map or slice.
+// Does nothing if this isn't an object.
+func (j Json) CopyInto(target *map[string]interface{}) {
+	m, ok := j.asMap()
+	if !ok || target == nil {
+		return
+	}
+
+	if *target == nil {
+		*target = make(map[string]interface{}, len(m))
+	}
+	for k, v := range m {
+		(*target)[k] = deepCopy(v)
+	}
+}
+
+// normalize turns a json.Marshal-able Go value into decoded JSON data
+// (a Json is deep-copied, an undefined one giving null).
+// unlike with NewJson, a string or []byte is a value, not JSON text to parse
+func normalize(value interface{}) (interface{}, error) {
+	if j, ok := value.(Json); ok {
+		return deepCopy(j.data), nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(b)
+}
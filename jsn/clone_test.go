@@ -0,0 +1,56 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	j, err := NewJson(`{"a": 1, "nested": {"b": [1, {"c": "x"}]}}`)
+	require.NoError(t, err)
+
+	clone := j.Clone()
+	assert.True(t, clone.Equal(j))
+
+	nested := clone.K("nested")
+	require.NoError(t, nested.Set("new", true))
+	clone.K("nested").K("b").Raw().([]interface{})[0] = "changed"
+	c := clone.K("nested").K("b").I(1)
+	require.NoError(t, c.Set("c", "y"))
+	require.NoError(t, clone.Set("a", 2))
+
+	assert.Equal(t, `{"a":1,"nested":{"b":[1,{"c":"x"}]}}`, j.Stringify())
+	assert.Equal(t, `{"a":2,"nested":{"b":["changed",{"c":"y"}],"new":true}}`, clone.Stringify())
+
+	assert.True(t, Json{}.Clone().Undefined())
+	assert.True(t, j.K("missing").Clone().Undefined())
+	assert.Equal(t, "x", j.GetPath("nested.b[1].c").Clone().String().Value)
+}
+
+func TestCopyInto(t *testing.T) {
+	j, err := NewJson(`{"a": 1, "nested": {"b": [1, 2]}}`)
+	require.NoError(t, err)
+
+	target := map[string]interface{}{"a": "old", "keep": true}
+	j.CopyInto(&target)
+
+	assert.Len(t, target, 3)
+	assert.Equal(t, true, target["keep"])
+	assert.Equal(t, j.K("a").Raw(), target["a"])
+	assert.Equal(t, j.K("nested").Raw(), target["nested"])
+
+	target["nested"].(map[string]interface{})["b"].([]interface{})[0] = "changed"
+	assert.Equal(t, 1, j.K("nested").K("b").I(0).Int().Value)
+
+	var empty map[string]interface{}
+	j.CopyInto(&empty)
+	assert.Len(t, empty, 2)
+
+	untouched := map[string]interface{}{"x": 1}
+	j.K("a").CopyInto(&untouched)
+	assert.Equal(t, map[string]interface{}{"x": 1}, untouched)
+
+	j.CopyInto(nil)
+}
@@ -0,0 +1,251 @@
+package jsn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type durationConfig struct {
+	unit time.Duration
+}
+
+// DurationOption configures Duration
+type DurationOption func(*durationConfig)
+
+// DurationUnit sets the unit of numeric durations, e.g. time.Second for {"timeout": 30},
+// or time.Nanosecond to read numbers like a marshaled time.Duration.
+// The default is time.Millisecond
+func DurationUnit(unit time.Duration) DurationOption {
+	return func(c *durationConfig) {
+		c.unit = unit
+	}
+}
+
+// Duration returns the value as a time.Duration, and whether it's valid:
+// a string is parsed with time.ParseDuration ("30s", "1h30m"), and a number is a count
+// of milliseconds, or of the unit given with DurationUnit (fractions are rounded to the
+// nearest nanosecond).
+// returns (0, false) for other types, invalid strings, or numbers out of the Duration range.
+func (j Json) Duration(opts ...DurationOption) (time.Duration, bool) {
+	c := durationConfig{unit: time.Millisecond}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if !j.exists {
+		return 0, false
+	}
+
+	if s, ok := j.data.(string); ok {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, false
+		}
+		return d, true
+	}
+
+	f, ok := numberValue(j.data)
+	if !ok {
+		return 0, false
+	}
+	ns := math.Round(f * float64(c.unit))
+	if ns < math.MinInt64 || ns >= math.MaxInt64 {
+		return 0, false
+	}
+
+	return time.Duration(ns), true
+}
+
+// Time parses a string value as an RFC 3339 timestamp, like "2006-01-02T15:04:05Z07:00".
+// returns the zero time and false for other types, or strings in another format.
+func (j Json) Time() (time.Time, bool) {
+	return j.TimeFormat(time.RFC3339)
+}
+
+// TimeFormat is like Time, for strings in another layout (see time.Parse)
+func (j Json) TimeFormat(layout string) (time.Time, bool) {
+	s := j.String()
+	if !s.IsValid {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(layout, s.Value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+var byteSizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// ByteSize returns the value as a count of bytes, and whether it's valid.
+// A string is a number followed by an optional, case-insensitive unit suffix:
+// B, KB, MB, GB and TB are decimal (1KB = 1000 bytes), while KiB, MiB, GiB and TiB are
+// binary (1KiB = 1024 bytes), e.g. "256MB", "1.5GiB" or "512". Space between the number
+// and the suffix is allowed, and fractions are rounded to the nearest byte.
+// A number is a raw count of bytes, and must be a whole number.
+// returns (0, false) for other types, unknown suffixes, negative sizes or overflows.
+func (j Json) ByteSize() (int64, bool) {
+	if !j.exists {
+		return 0, false
+	}
+
+	var size float64
+	if s, ok := j.data.(string); ok {
+		s = strings.TrimSpace(s)
+		i := strings.IndexFunc(s, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		if i < 0 {
+			i = len(s)
+		}
+
+		n, err := strconv.ParseFloat(s[:i], 64)
+		if err != nil {
+			return 0, false
+		}
+		unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+		if !ok {
+			return 0, false
+		}
+		size = math.Round(n * unit)
+	} else {
+		n, ok := numberValue(j.data)
+		if !ok || n != math.Trunc(n) {
+			return 0, false
+		}
+		size = n
+	}
+
+	if size < 0 || size >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(size), true
+}
+
+// ParseInt is like Int, but also accepts a string holding a JSON number, like {"count": "42"},
+// as returned by APIs that quote their numbers. Quoted floats are truncated like numbers,
+// so "2.5" gives 2. Whitespace around the number is allowed, anything else
+// (including "", "0x10", "NaN" or "1,000") is not valid.
+func (j Json) ParseInt() Int {
+	return j.numeric().Int()
+}
+
+// ParseInt64 is like Int64, but also accepts a string holding a JSON number (see ParseInt)
+func (j Json) ParseInt64() Int64 {
+	return j.numeric().Int64()
+}
+
+// ParseFloat64 is like Float64, but also accepts a string holding a JSON number (see ParseInt)
+func (j Json) ParseFloat64() Float64 {
+	return j.numeric().Float64()
+}
+
+// numeric returns the number held by a numeric string, or this Json as is for other values
+func (j Json) numeric() Json {
+	s, ok := j.data.(string)
+	if !ok {
+		return j
+	}
+
+	n, err := decode([]byte(s))
+	if _, isNumber := n.(json.Number); err != nil || !isNumber {
+		return Json{}
+	}
+	return Json{data: n, exists: true}
+}
+
+// ToFloat64Map converts an object whose values are all numbers, like scores or a feature
+// vector keyed by name, to a map[string]float64.
+// The requirement is strict: returns nil and false if any value isn't a number
+// (numeric strings, bools and nulls included), or if this isn't an object.
+func (j Json) ToFloat64Map() (map[string]float64, bool) {
+	m, ok := j.asMap()
+	if !ok {
+		return nil, false
+	}
+
+	floats := make(map[string]float64, len(m))
+	for k, v := range m {
+		f, ok := numberValue(v)
+		if !ok {
+			return nil, false
+		}
+		floats[k] = f
+	}
+
+	return floats, true
+}
+
+// ToAnonymousStruct returns the value as plain, typed Go values mirroring the JSON, handy
+// for printing with %+v or for reflection-based tools: objects become map[string]interface{},
+// arrays []interface{}, and numbers are narrowed to int64 when integral and within its range
+// (1, 1.0 or 1e3), or float64 otherwise. Strings, bools and null are kept as is.
+// Unlike Raw(), no json.Number is left in the result. It's a new tree, sharing no state with
+// this Json. Structs aren't generated: the result holds maps and slices.
+// Returns an error if this Json is undefined, or holds a number that can't be parsed.
+func (j Json) ToAnonymousStruct() (interface{}, error) {
+	if !j.exists {
+		return nil, errors.New("value is undefined")
+	}
+
+	return anonymousValue(j.data)
+}
+
+func anonymousValue(data interface{}) (interface{}, error) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			a, err := anonymousValue(e)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = a
+		}
+		return m, nil
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, e := range v {
+			a, err := anonymousValue(e)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = a
+		}
+		return s, nil
+	case float64, json.Number:
+		if n, ok := v.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				return i, nil
+			}
+		}
+		f, ok := numberValue(v)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %v", v)
+		}
+		if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int64(f), nil
+		}
+		return f, nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,231 @@
+package jsn
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuration(t *testing.T) {
+	j, err := NewJson(`{
+		"timeout": "30s",
+		"interval": "1h30m",
+		"delay": 250,
+		"fraction": 1.5,
+		"bad": "thirty seconds",
+		"flag": true,
+		"huge": 1e30
+	}`)
+	require.NoError(t, err)
+
+	d, ok := j.K("timeout").Duration()
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+
+	d, ok = j.K("interval").Duration()
+	assert.True(t, ok)
+	assert.Equal(t, 90*time.Minute, d)
+
+	d, ok = j.K("delay").Duration()
+	assert.True(t, ok)
+	assert.Equal(t, 250*time.Millisecond, d)
+
+	d, ok = j.K("delay").Duration(DurationUnit(time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 250*time.Second, d)
+
+	d, ok = j.K("fraction").Duration(DurationUnit(time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, d)
+
+	d, ok = j.K("timeout").Duration(DurationUnit(time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d, "the unit only applies to numbers")
+
+	for _, key := range []string{"bad", "flag", "huge", "no"} {
+		d, ok = j.K(key).Duration()
+		assert.False(t, ok, key)
+		assert.Equal(t, time.Duration(0), d, key)
+	}
+}
+
+func TestDurationStringsAndNanoseconds(t *testing.T) {
+	j, err := NewJson(`["5m", "-1.5h", "300ms", "5", "5 m", "", null, {"d": "1s"}, ["1s"], 1500000000]`)
+	require.NoError(t, err)
+
+	expected := []time.Duration{5 * time.Minute, -90 * time.Minute, 300 * time.Millisecond}
+	for i, e := range expected {
+		d, ok := j.I(i).Duration()
+		assert.True(t, ok, i)
+		assert.Equal(t, e, d, i)
+	}
+
+	for i := len(expected); i < 9; i++ {
+		d, ok := j.I(i).Duration()
+		assert.False(t, ok, i)
+		assert.Equal(t, time.Duration(0), d, i)
+	}
+
+	d, ok := j.I(9).Duration(DurationUnit(time.Nanosecond))
+	assert.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, d)
+
+	marshaled, err := NewJson(map[string]time.Duration{"timeout": 90 * time.Second})
+	require.NoError(t, err)
+	d, ok = marshaled.K("timeout").Duration(DurationUnit(time.Nanosecond))
+	assert.True(t, ok)
+	assert.Equal(t, 90*time.Second, d)
+}
+
+func TestTime(t *testing.T) {
+	j, err := NewJson(`{"created": "2023-04-05T06:07:08Z", "offset": "2023-04-05T08:07:08.5+02:00", "day": "2023-04-05", "epoch": 1680674828}`)
+	require.NoError(t, err)
+
+	created, ok := j.K("created").Time()
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC), created)
+
+	offset, ok := j.K("offset").Time()
+	assert.True(t, ok)
+	assert.True(t, created.Add(500*time.Millisecond).Equal(offset))
+
+	for _, key := range []string{"day", "epoch", "missing"} {
+		tm, ok := j.K(key).Time()
+		assert.False(t, ok, key)
+		assert.True(t, tm.IsZero(), key)
+	}
+
+	day, ok := j.K("day").TimeFormat("2006-01-02")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC), day)
+
+	_, ok = j.K("created").TimeFormat("2006-01-02")
+	assert.False(t, ok)
+}
+
+func TestByteSize(t *testing.T) {
+	j, err := NewJson(`{
+		"cache": "256MB",
+		"heap": "1.5GiB",
+		"raw": 4096,
+		"lower": "64kib",
+		"spaced": " 2 KB ",
+		"bytes": "10B",
+		"plain": "512",
+		"half": 1.5,
+		"negative": -1,
+		"unknown": "5XB",
+		"empty": "",
+		"dot": ".",
+		"flag": true
+	}`)
+	require.NoError(t, err)
+
+	cases := map[string]int64{
+		"cache":  256000000,
+		"heap":   1610612736,
+		"raw":    4096,
+		"lower":  65536,
+		"spaced": 2000,
+		"bytes":  10,
+		"plain":  512,
+	}
+	for key, expected := range cases {
+		size, ok := j.K(key).ByteSize()
+		assert.True(t, ok, key)
+		assert.Equal(t, expected, size, key)
+	}
+
+	for _, key := range []string{"half", "negative", "unknown", "empty", "dot", "flag", "no"} {
+		size, ok := j.K(key).ByteSize()
+		assert.False(t, ok, key)
+		assert.Equal(t, int64(0), size, key)
+	}
+}
+
+func TestParseNumbers(t *testing.T) {
+	j, err := NewJson(`{
+		"int": "42", "neg": "-7", "big": "9007199254740993", "float": "2.5", "exp": "1e3", "spaced": " 8 ",
+		"number": 3.9, "text": "42abc", "empty": "", "hex": "0x10", "nan": "NaN", "quoted": "\"1\"", "bool": true
+	}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, Int{42, true}, j.K("int").ParseInt())
+	assert.Equal(t, Int{-7, true}, j.K("neg").ParseInt())
+	assert.Equal(t, Int64{9007199254740993, true}, j.K("big").ParseInt64())
+	assert.Equal(t, Int{2, true}, j.K("float").ParseInt())
+	assert.Equal(t, Float64{2.5, true}, j.K("float").ParseFloat64())
+	assert.Equal(t, Float64{1000, true}, j.K("exp").ParseFloat64())
+	assert.Equal(t, Int{8, true}, j.K("spaced").ParseInt())
+	assert.Equal(t, Float64{42, true}, j.K("int").ParseFloat64())
+
+	// actual numbers are valid, as with Int
+	assert.Equal(t, Int{3, true}, j.K("number").ParseInt())
+	assert.Equal(t, Float64{3.9, true}, j.K("number").ParseFloat64())
+
+	for _, key := range []string{"text", "empty", "hex", "nan", "quoted", "bool", "missing"} {
+		assert.False(t, j.K(key).ParseInt().IsValid, key)
+		assert.False(t, j.K(key).ParseInt64().IsValid, key)
+		assert.False(t, j.K(key).ParseFloat64().IsValid, key)
+	}
+
+	assert.False(t, j.K("int").Int().IsValid, "Int is unchanged")
+}
+
+func TestToFloat64Map(t *testing.T) {
+	j, err := NewJson(`{"scores": {"a": 1, "b": 0.25, "c": -3e2}, "mixed": {"a": 1, "b": "2"}, "empty": {}}`)
+	require.NoError(t, err)
+
+	m, ok := j.K("scores").ToFloat64Map()
+	assert.True(t, ok)
+	assert.Equal(t, map[string]float64{"a": 1, "b": 0.25, "c": -300}, m)
+
+	m, ok = decodeUseNumber(t, `{"big": 12345678901, "x": 1.5}`).ToFloat64Map()
+	assert.True(t, ok)
+	assert.Equal(t, map[string]float64{"big": 12345678901, "x": 1.5}, m)
+
+	m, ok = j.K("mixed").ToFloat64Map()
+	assert.False(t, ok)
+	assert.Nil(t, m)
+
+	m, ok = j.K("empty").ToFloat64Map()
+	assert.True(t, ok)
+	assert.Equal(t, map[string]float64{}, m)
+
+	_, ok = j.K("scores").K("a").ToFloat64Map()
+	assert.False(t, ok)
+}
+
+func TestToAnonymousStruct(t *testing.T) {
+	j, err := NewJson(`{"id": 42, "price": 9.99, "whole": 3.0, "exp": 1e3, "big": 12345678901234567,
+		"huge": 1e30, "name": "x", "ok": true, "none": null, "items": [1, 2.5, {"n": -7}]}`)
+	require.NoError(t, err)
+
+	v, err := j.ToAnonymousStruct()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"id":    int64(42),
+		"price": 9.99,
+		"whole": int64(3),
+		"exp":   int64(1000),
+		"big":   int64(12345678901234567),
+		"huge":  1e30,
+		"name":  "x",
+		"ok":    true,
+		"none":  nil,
+		"items": []interface{}{int64(1), 2.5, map[string]interface{}{"n": int64(-7)}},
+	}, v)
+
+	v, err = j.K("price").ToAnonymousStruct()
+	require.NoError(t, err)
+	assert.Equal(t, 9.99, v)
+
+	_, err = j.K("missing").ToAnonymousStruct()
+	assert.EqualError(t, err, "value is undefined")
+
+	_, err = Json{data: []interface{}{json.Number("nope")}, exists: true}.ToAnonymousStruct()
+	assert.EqualError(t, err, "invalid number nope")
+}
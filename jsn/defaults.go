@@ -0,0 +1,77 @@
+package jsn
+
+// logDefault reports, via logf, that a default value is used instead of this Json's value.
+// the message includes the value's JSON Pointer when it's known (observed documents)
+func (j Json) logDefault(logf func(format string, args ...interface{}), expected string, def interface{}) {
+	if logf == nil {
+		return
+	}
+
+	found := "missing"
+	if j.exists {
+		found = j.Type().String()
+	}
+
+	if j.obs != nil {
+		logf("jsn: no valid %s at %q (%s), using default %v", expected, j.obs.pointer, found, def)
+	} else {
+		logf("jsn: no valid %s (%s), using default %v", expected, found, def)
+	}
+}
+
+// StringOrLog returns the string value, or def if it isn't a valid string,
+// in which case the use of the default is reported via logf.
+// The log message names what was found instead (e.g. "missing" or "number"), and the JSON Pointer
+// of the value if it's known, which is only the case for documents created by NewJsonObserved.
+func (j Json) StringOrLog(def string, logf func(format string, args ...interface{})) string {
+	if v := j.String(); v.IsValid {
+		return v.Value
+	}
+
+	j.logDefault(logf, "string", def)
+	return def
+}
+
+// IntOrLog returns the int value, or def (reported via logf) if it isn't valid.
+// See StringOrLog for the logging behavior
+func (j Json) IntOrLog(def int, logf func(format string, args ...interface{})) int {
+	if v := j.Int(); v.IsValid {
+		return v.Value
+	}
+
+	j.logDefault(logf, "int", def)
+	return def
+}
+
+// Int64OrLog returns the int64 value, or def (reported via logf) if it isn't valid.
+// See StringOrLog for the logging behavior
+func (j Json) Int64OrLog(def int64, logf func(format string, args ...interface{})) int64 {
+	if v := j.Int64(); v.IsValid {
+		return v.Value
+	}
+
+	j.logDefault(logf, "int64", def)
+	return def
+}
+
+// Float64OrLog returns the float64 value, or def (reported via logf) if it isn't valid.
+// See StringOrLog for the logging behavior
+func (j Json) Float64OrLog(def float64, logf func(format string, args ...interface{})) float64 {
+	if v := j.Float64(); v.IsValid {
+		return v.Value
+	}
+
+	j.logDefault(logf, "float64", def)
+	return def
+}
+
+// BoolOrLog returns the bool value, or def (reported via logf) if it isn't valid.
+// See StringOrLog for the logging behavior
+func (j Json) BoolOrLog(def bool, logf func(format string, args ...interface{})) bool {
+	if v := j.Bool(); v.IsValid {
+		return v.Value
+	}
+
+	j.logDefault(logf, "bool", def)
+	return def
+}
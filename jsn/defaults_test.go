@@ -0,0 +1,44 @@
+package jsn
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrLog(t *testing.T) {
+	var logs []string
+	logf := func(format string, args ...interface{}) {
+		logs = append(logs, fmt.Sprintf(format, args...))
+	}
+
+	j, err := NewJson(`{"port": 8080, "host": "h", "debug": "yes", "ratio": 0.5, "big": 5000000000}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, 8080, j.K("port").IntOrLog(80, logf))
+	assert.Equal(t, "h", j.K("host").StringOrLog("localhost", logf))
+	assert.Equal(t, 0.5, j.K("ratio").Float64OrLog(1, logf))
+	assert.Equal(t, int64(5000000000), j.K("big").Int64OrLog(1, logf))
+	assert.Empty(t, logs)
+
+	assert.Equal(t, 30, j.K("timeout").IntOrLog(30, logf))
+	assert.False(t, j.K("debug").BoolOrLog(false, logf))
+	assert.Equal(t, "x", j.K("port").StringOrLog("x", nil))
+	assert.Equal(t, []string{
+		"jsn: no valid int (missing), using default 30",
+		"jsn: no valid bool (string), using default false",
+	}, logs)
+
+	logs = nil
+	o, err := NewJsonObserved(`{"server": {"port": "80"}}`, func(string) {})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(443), o.K("server").K("port").Int64OrLog(443, logf))
+	assert.Equal(t, 1.5, o.K("server").K("ratio").Float64OrLog(1.5, logf))
+	assert.Equal(t, []string{
+		`jsn: no valid int64 at "/server/port" (string), using default 443`,
+		`jsn: no valid float64 at "/server/ratio" (missing), using default 1.5`,
+	}, logs)
+}
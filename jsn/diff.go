@@ -0,0 +1,88 @@
+package jsn
+
+// Diff returns an object describing how other differs from this Json:
+//
+//	{
+//	  "added":   {"a.c": 3},                       // only in other
+//	  "removed": {"a.b[1]": "x"},                  // only in this Json
+//	  "changed": {"a.d": {"from": 1, "to": 2}}     // in both, with different values
+//	}
+//
+// Objects are compared key by key and arrays index by index, recursively, and the
+// differences are keyed by their dotted path, with the syntax of GetPath ("" for the root).
+// A value whose type changed (e.g. an object replaced by a string) is reported as changed
+// as a whole. Values are compared like Equal, so 1 and 1.0 are no change.
+// The three keys are always present, holding empty objects when there are no differences.
+func (j Json) Diff(other Json) Json {
+	d := differ{
+		added:   map[string]interface{}{},
+		removed: map[string]interface{}{},
+		changed: map[string]interface{}{},
+	}
+	d.diff(nil, j, other)
+
+	return Json{data: map[string]interface{}{
+		"added":   d.added,
+		"removed": d.removed,
+		"changed": d.changed,
+	}, exists: true}
+}
+
+type differ struct {
+	added, removed, changed map[string]interface{}
+}
+
+func (d *differ) diff(path []pathSegment, a, b Json) {
+	key := formatPath(path)
+
+	switch {
+	case !a.exists && !b.exists:
+		return
+	case !a.exists:
+		d.added[key] = deepCopy(b.data)
+		return
+	case !b.exists:
+		d.removed[key] = deepCopy(a.data)
+		return
+	}
+
+	am, aIsMap := a.data.(map[string]interface{})
+	bm, bIsMap := b.data.(map[string]interface{})
+	if aIsMap && bIsMap {
+		keys := map[string]bool{}
+		for k := range am {
+			keys[k] = true
+		}
+		for k := range bm {
+			keys[k] = true
+		}
+		for k := range keys {
+			d.diff(appendSegment(path, pathSegment{key: k}), a.K(k), b.K(k))
+		}
+		return
+	}
+
+	aa, aIsArray := a.data.([]interface{})
+	ba, bIsArray := b.data.([]interface{})
+	if aIsArray && bIsArray {
+		n := len(aa)
+		if len(ba) > n {
+			n = len(ba)
+		}
+		for i := 0; i < n; i++ {
+			d.diff(appendSegment(path, pathSegment{index: i, isIndex: true}), a.I(i), b.I(i))
+		}
+		return
+	}
+
+	if !equalValues(a.data, b.data) {
+		d.changed[key] = map[string]interface{}{"from": deepCopy(a.data), "to": deepCopy(b.data)}
+	}
+}
+
+// appendSegment returns a new path, leaving path untouched
+func appendSegment(path []pathSegment, s pathSegment) []pathSegment {
+	p := make([]pathSegment, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, s)
+}
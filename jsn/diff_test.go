@@ -0,0 +1,50 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	before, err := NewJson(`{
+		"status": "pending",
+		"count": 1,
+		"spec": {"replicas": 2, "image": "app:1", "ports": [80, 443], "env": {"A": "1"}},
+		"labels": {"team": "x", "a.b": "dotted"},
+		"owner": {"name": "bob"}
+	}`)
+	require.NoError(t, err)
+	after, err := NewJson(`{
+		"status": "running",
+		"count": 1.0,
+		"spec": {"replicas": 3, "image": "app:1", "ports": [80], "env": {"A": "1", "B": "2"}},
+		"labels": {"team": "x", "a.b": "moved"},
+		"owner": "alice",
+		"ready": true
+	}`)
+	require.NoError(t, err)
+
+	diff := before.Diff(after)
+	expected, err := NewJson(`{
+		"added": {"ready": true, "spec.env.B": "2"},
+		"removed": {"spec.ports[1]": 443},
+		"changed": {
+			"status": {"from": "pending", "to": "running"},
+			"spec.replicas": {"from": 2, "to": 3},
+			"labels.a\\.b": {"from": "dotted", "to": "moved"},
+			"owner": {"from": {"name": "bob"}, "to": "alice"}
+		}
+	}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(diff), diff.Pretty())
+
+	// paths can be fed back to GetPath
+	assert.Equal(t, "moved", after.GetPath(`labels.a\.b`).String().Value)
+
+	assert.Equal(t, `{"added":{},"changed":{},"removed":{}}`, before.Diff(before).Stringify())
+	assert.Equal(t, `{"added":{},"changed":{"":{"from":1,"to":2}},"removed":{}}`,
+		Json{data: 1.0, exists: true}.Diff(Json{data: 2.0, exists: true}).Stringify())
+	assert.Equal(t, `{"added":{"":[1]},"changed":{},"removed":{}}`, Json{}.Diff(Json{data: []interface{}{1.0}, exists: true}).Stringify())
+}
@@ -0,0 +1,161 @@
+package jsn
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NewJsonFromDotEnv parses a .env file into a flat object of strings.
+// Every line is KEY=VALUE, optionally prefixed with "export ". Blank lines and lines
+// starting with # are skipped. Values may be:
+//   - unquoted: surrounding spaces are trimmed, and a " #" starts a comment
+//   - single-quoted: taken literally, without escapes
+//   - double-quoted: \n, \r, \t, \" and \\ are unescaped (other backslashes are kept)
+//
+// A quoted value may only be followed by spaces or a comment. Quoted values can't span lines.
+// When a key appears more than once, the last value wins.
+func NewJsonFromDotEnv(r io.Reader) (Json, error) {
+	m := map[string]interface{}{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if n == 1 {
+			line = strings.TrimPrefix(line, string(utf8BOM))
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, err := parseDotEnvLine(line)
+		if err != nil {
+			return Json{}, fmt.Errorf("line %d: %v", n, err)
+		}
+		m[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return Json{}, err
+	}
+
+	return Json{data: m, exists: true}, nil
+}
+
+func parseDotEnvLine(line string) (string, string, error) {
+	if strings.HasPrefix(line, "export ") {
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+	}
+
+	eq := strings.IndexByte(line, '=')
+	if eq < 0 {
+		return "", "", errors.New("expected KEY=VALUE")
+	}
+	key := strings.TrimSpace(line[:eq])
+	if !validDotEnvKey(key) {
+		return "", "", fmt.Errorf("invalid key %q", key)
+	}
+
+	raw := strings.TrimSpace(line[eq+1:])
+	if raw == "" || (raw[0] != '"' && raw[0] != '\'') {
+		if i := strings.Index(raw, " #"); i >= 0 {
+			raw = strings.TrimSpace(raw[:i])
+		}
+		return key, raw, nil
+	}
+
+	quote := raw[0]
+	var b strings.Builder
+	i := 1
+	for ; i < len(raw) && raw[i] != quote; i++ {
+		c := raw[i]
+		if quote == '"' && c == '\\' && i+1 < len(raw) {
+			i++
+			switch raw[i] {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case '"', '\\':
+				c = raw[i]
+			default:
+				b.WriteByte('\\')
+				c = raw[i]
+			}
+		}
+		b.WriteByte(c)
+	}
+	if i == len(raw) {
+		return "", "", fmt.Errorf("unterminated quoted value for key %q", key)
+	}
+
+	if rest := strings.TrimSpace(raw[i+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", "", fmt.Errorf("unexpected %q after quoted value for key %q", rest, key)
+	}
+
+	return key, b.String(), nil
+}
+
+func validDotEnvKey(key string) bool {
+	return key != "" && !strings.ContainsAny(key, "= \t\r\n#\"'")
+}
+
+// ToDotEnv formats a flat object as a .env file, one KEY=VALUE line per key, sorted by key.
+// Strings are used as is, numbers and bools are formatted, and null becomes an empty value.
+// Values that are empty or made of plain characters are written unquoted; others are
+// double-quoted, escaping newlines, tabs, quotes and backslashes, so NewJsonFromDotEnv
+// reads them back unchanged.
+// Nested objects or arrays, and keys that can't be used in a .env file, are an error.
+func (j Json) ToDotEnv() (string, error) {
+	m, ok := j.asMap()
+	if !ok {
+		return "", errors.New("value is not an object")
+	}
+
+	var b strings.Builder
+	for _, k := range sortedKeys(m) {
+		if !validDotEnvKey(k) {
+			return "", fmt.Errorf("invalid key %q", k)
+		}
+
+		switch m[k].(type) {
+		case map[string]interface{}:
+			return "", fmt.Errorf("key %q holds a nested object", k)
+		case []interface{}:
+			return "", fmt.Errorf("key %q holds a nested array", k)
+		}
+
+		s, err := envValue(m[k])
+		if err != nil {
+			return "", fmt.Errorf("key %q: %v", k, err)
+		}
+
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(quoteDotEnvValue(s))
+		b.WriteByte('\n')
+	}
+
+	return b.String(), nil
+}
+
+func quoteDotEnvValue(s string) string {
+	plain := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			strings.ContainsRune("_-.,:/@+", r))
+	}) < 0
+	if plain {
+		return s
+	}
+
+	return `"` + strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	).Replace(s) + `"`
+}
@@ -0,0 +1,94 @@
+package jsn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJsonFromDotEnv(t *testing.T) {
+	env := `
+# database settings
+DB_HOST=localhost
+DB_PORT = 5432   # inline comment
+export APP_NAME=svc
+
+GREETING="hello # not a comment"
+MULTI="line1\nline2\t\"quoted\" \\ \$HOME"
+LITERAL='raw \n $HOME' # comment
+EMPTY=
+EMPTY_QUOTED=""
+URL=http://example.com/a#frag
+DB_HOST=override
+`
+	j, err := NewJsonFromDotEnv(strings.NewReader(env))
+	require.NoError(t, err)
+
+	expected, err := NewJson(map[string]interface{}{
+		"DB_HOST":      "override",
+		"DB_PORT":      "5432",
+		"APP_NAME":     "svc",
+		"GREETING":     "hello # not a comment",
+		"MULTI":        "line1\nline2\t\"quoted\" \\ \\$HOME",
+		"LITERAL":      `raw \n $HOME`,
+		"EMPTY":        "",
+		"EMPTY_QUOTED": "",
+		"URL":          "http://example.com/a#frag",
+	})
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(j), j.Pretty())
+}
+
+func TestNewJsonFromDotEnvErrors(t *testing.T) {
+	_, err := NewJsonFromDotEnv(strings.NewReader("A=1\nnot a pair\n"))
+	assert.EqualError(t, err, "line 2: expected KEY=VALUE")
+
+	_, err = NewJsonFromDotEnv(strings.NewReader(`A="open`))
+	assert.EqualError(t, err, `line 1: unterminated quoted value for key "A"`)
+
+	_, err = NewJsonFromDotEnv(strings.NewReader(`A="x" y`))
+	assert.EqualError(t, err, `line 1: unexpected "y" after quoted value for key "A"`)
+
+	_, err = NewJsonFromDotEnv(strings.NewReader(`MY KEY=1`))
+	assert.EqualError(t, err, `line 1: invalid key "MY KEY"`)
+}
+
+func TestToDotEnv(t *testing.T) {
+	j, err := NewJson(`{"PORT": 8080, "DEBUG": true, "NAME": "svc", "NONE": null,
+		"MSG": "hello world", "TRICKY": "a\"b\\c\nd #e", "PATH": "/usr/bin:/bin"}`)
+	require.NoError(t, err)
+
+	s, err := j.ToDotEnv()
+	require.NoError(t, err)
+	assert.Equal(t, `DEBUG=true
+MSG="hello world"
+NAME=svc
+NONE=
+PATH=/usr/bin:/bin
+PORT=8080
+TRICKY="a\"b\\c\nd #e"
+`, s)
+
+	back, err := NewJsonFromDotEnv(strings.NewReader(s))
+	require.NoError(t, err)
+	for _, k := range []string{"MSG", "NAME", "PATH", "TRICKY"} {
+		assert.Equal(t, j.K(k).String(), back.K(k).String(), k)
+	}
+	assert.Equal(t, "8080", back.K("PORT").String().Value)
+	assert.Equal(t, "", back.K("NONE").String().Value)
+
+	j, err = NewJson(`{"DB": {"HOST": "h"}}`)
+	require.NoError(t, err)
+	_, err = j.ToDotEnv()
+	assert.EqualError(t, err, `key "DB" holds a nested object`)
+
+	j, err = NewJson(`{"A=B": "x"}`)
+	require.NoError(t, err)
+	_, err = j.ToDotEnv()
+	assert.EqualError(t, err, `invalid key "A=B"`)
+
+	_, err = j.K("A=B").ToDotEnv()
+	assert.EqualError(t, err, "value is not an object")
+}
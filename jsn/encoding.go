@@ -0,0 +1,106 @@
+package jsn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type textEncoding int
+
+const (
+	encUTF8 textEncoding = iota
+	encUTF16BE
+	encUTF16LE
+	encUTF32BE
+	encUTF32LE
+)
+
+// detectEncoding finds the encoding of a JSON text from its byte order mark, or if there's none,
+// from the pattern of zero bytes at its start (a JSON text starts with two ASCII characters, RFC 4627).
+// returns the encoding and the length of the BOM
+func detectEncoding(data []byte) (textEncoding, int) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0x00, 0x00, 0xFE, 0xFF}):
+		return encUTF32BE, 4
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE, 0x00, 0x00}):
+		return encUTF32LE, 4
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return encUTF16BE, 2
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return encUTF16LE, 2
+	case bytes.HasPrefix(data, utf8BOM):
+		return encUTF8, 3
+	}
+
+	if len(data) >= 4 {
+		switch {
+		case data[0] == 0 && data[1] == 0 && data[2] == 0:
+			return encUTF32BE, 0
+		case data[1] == 0 && data[2] == 0 && data[3] == 0:
+			return encUTF32LE, 0
+		}
+	}
+	if len(data) >= 2 {
+		switch {
+		case data[0] == 0:
+			return encUTF16BE, 0
+		case data[1] == 0:
+			return encUTF16LE, 0
+		}
+	}
+
+	return encUTF8, 0
+}
+
+// NewJsonAutoEncoding parses a JSON text that may be encoded in UTF-8, UTF-16 (BE or LE)
+// or UTF-32 (BE or LE), as allowed by RFC 8259 for texts exchanged within closed systems.
+// The encoding is taken from the byte order mark if there's one, and otherwise detected
+// from the zero bytes in the first characters (which are ASCII in any JSON text).
+// The text is transcoded to UTF-8 before decoding, so for plain UTF-8 input prefer NewJson.
+func NewJsonAutoEncoding(data []byte) (Json, error) {
+	enc, bomLen := detectEncoding(data)
+	data = data[bomLen:]
+
+	var utf8Data []byte
+	switch enc {
+	case encUTF8:
+		utf8Data = data
+	case encUTF16BE, encUTF16LE:
+		if len(data)%2 != 0 {
+			return Json{}, errors.New("invalid UTF-16 input: odd number of bytes")
+		}
+
+		var order binary.ByteOrder = binary.BigEndian
+		if enc == encUTF16LE {
+			order = binary.LittleEndian
+		}
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			units[i] = order.Uint16(data[2*i:])
+		}
+		utf8Data = []byte(string(utf16.Decode(units)))
+	case encUTF32BE, encUTF32LE:
+		if len(data)%4 != 0 {
+			return Json{}, errors.New("invalid UTF-32 input: length is not a multiple of 4")
+		}
+
+		var order binary.ByteOrder = binary.BigEndian
+		if enc == encUTF32LE {
+			order = binary.LittleEndian
+		}
+		utf8Data = make([]byte, 0, len(data)/4)
+		for i := 0; i < len(data); i += 4 {
+			r := rune(order.Uint32(data[i:]))
+			if !utf8.ValidRune(r) {
+				return Json{}, fmt.Errorf("invalid UTF-32 input: bad code point %#x at offset %d", uint32(r), i)
+			}
+			utf8Data = append(utf8Data, string(r)...)
+		}
+	}
+
+	return NewJson(utf8Data)
+}
@@ -0,0 +1,75 @@
+package jsn
+
+import (
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const encodingFixture = `{"name": "Gö𝄞pher", "n": 42}`
+
+func encodeUTF16(s string, order binary.ByteOrder, bom bool) []byte {
+	var out []byte
+	units := utf16.Encode([]rune(s))
+	if bom {
+		units = append([]uint16{0xFEFF}, units...)
+	}
+	for _, u := range units {
+		b := make([]byte, 2)
+		order.PutUint16(b, u)
+		out = append(out, b...)
+	}
+	return out
+}
+
+func encodeUTF32(s string, order binary.ByteOrder, bom bool) []byte {
+	var out []byte
+	runes := []rune(s)
+	if bom {
+		runes = append([]rune{0xFEFF}, runes...)
+	}
+	for _, r := range runes {
+		b := make([]byte, 4)
+		order.PutUint32(b, uint32(r))
+		out = append(out, b...)
+	}
+	return out
+}
+
+func TestNewJsonAutoEncoding(t *testing.T) {
+	inputs := map[string][]byte{
+		"utf-8":              []byte(encodingFixture),
+		"utf-8 bom":          append([]byte{0xEF, 0xBB, 0xBF}, encodingFixture...),
+		"utf-16be":           encodeUTF16(encodingFixture, binary.BigEndian, false),
+		"utf-16be bom":       encodeUTF16(encodingFixture, binary.BigEndian, true),
+		"utf-16le":           encodeUTF16(encodingFixture, binary.LittleEndian, false),
+		"utf-16le bom":       encodeUTF16(encodingFixture, binary.LittleEndian, true),
+		"utf-32be":           encodeUTF32(encodingFixture, binary.BigEndian, false),
+		"utf-32be bom":       encodeUTF32(encodingFixture, binary.BigEndian, true),
+		"utf-32le":           encodeUTF32(encodingFixture, binary.LittleEndian, false),
+		"utf-32le bom":       encodeUTF32(encodingFixture, binary.LittleEndian, true),
+		"utf-16le short":     encodeUTF16("7", binary.LittleEndian, false),
+		"utf-16be short bom": encodeUTF16("7", binary.BigEndian, true),
+	}
+
+	for name, data := range inputs {
+		j, err := NewJsonAutoEncoding(data)
+		require.NoError(t, err, name)
+
+		if name == "utf-16le short" || name == "utf-16be short bom" {
+			assert.Equal(t, 7, j.Int().Value, name)
+			continue
+		}
+		assert.Equal(t, "Gö𝄞pher", j.K("name").String().Value, name)
+		assert.Equal(t, 42, j.K("n").Int().Value, name)
+	}
+
+	_, err := NewJsonAutoEncoding(encodeUTF16(encodingFixture, binary.BigEndian, false)[1:])
+	assert.Error(t, err)
+
+	_, err = NewJsonAutoEncoding([]byte{0x00, 0x00, 0x00, '1', 0x00, 0x11, 0x00, 0x00})
+	assert.Error(t, err)
+}
@@ -0,0 +1,252 @@
+package jsn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type envConfig struct {
+	flatten    bool
+	separator  string
+	upperSnake bool
+	inferTypes bool
+}
+
+// EnvOption configures the conversion between Json and environment variables
+type EnvOption func(*envConfig)
+
+// EnvFlatten makes ToEnvList flatten nested objects and arrays instead of failing on them,
+// joining the keys (or array indexes) of every level with separator:
+// {"db": {"hosts": ["a"]}} with separator "_" gives db_hosts_0=a
+func EnvFlatten(separator string) EnvOption {
+	return func(c *envConfig) {
+		c.flatten = true
+		c.separator = separator
+	}
+}
+
+// EnvUpperSnake makes ToEnvList convert every key (each level, when flattening) to
+// UPPER_SNAKE_CASE: "dbHost", "db-host" and "db host" all become "DB_HOST"
+func EnvUpperSnake() EnvOption {
+	return func(c *envConfig) {
+		c.upperSnake = true
+	}
+}
+
+// EnvInferTypes makes FromEnv convert values that look like JSON numbers or bools
+// ("8080", "0.5", "true") to numbers and bools. Anything else stays a string.
+func EnvInferTypes() EnvOption {
+	return func(c *envConfig) {
+		c.inferTypes = true
+	}
+}
+
+// ToEnvList turns an object into a sorted list of "KEY=value" entries,
+// ready for exec.Cmd.Env or a container env block.
+// Strings are used as is, numbers and bools are formatted, and null becomes an empty value.
+// By default a nested object or array is an error; use EnvFlatten to flatten them instead
+// (empty nested objects/arrays then produce no entry).
+// Use EnvUpperSnake to convert the key names. Keys colliding after conversion are an error.
+func (j Json) ToEnvList(opts ...EnvOption) ([]string, error) {
+	var c envConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	m, ok := j.asMap()
+	if !ok {
+		return nil, errors.New("value is not an object")
+	}
+
+	vars := map[string]string{}
+	if err := c.collect(vars, "", m); err != nil {
+		return nil, err
+	}
+
+	list := make([]string, 0, len(vars))
+	for k, v := range vars {
+		list = append(list, k+"="+v)
+	}
+	sort.Strings(list)
+
+	return list, nil
+}
+
+func (c *envConfig) collect(vars map[string]string, prefix string, data interface{}) error {
+	add := func(key string, v interface{}) error {
+		if c.upperSnake {
+			key = upperSnake(key)
+		}
+		if prefix != "" {
+			key = prefix + c.separator + key
+		}
+		return c.collect(vars, key, v)
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if prefix != "" && !c.flatten {
+			return fmt.Errorf("key %q holds a nested object", prefix)
+		}
+		for k, e := range v {
+			if err := add(k, e); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if !c.flatten {
+			return fmt.Errorf("key %q holds a nested array", prefix)
+		}
+		for i, e := range v {
+			if err := add(strconv.Itoa(i), e); err != nil {
+				return err
+			}
+		}
+	default:
+		if _, exists := vars[prefix]; exists {
+			return fmt.Errorf("duplicate key %q", prefix)
+		}
+		s, err := envValue(v)
+		if err != nil {
+			return fmt.Errorf("key %q: %v", prefix, err)
+		}
+		vars[prefix] = s
+	}
+
+	return nil
+}
+
+func envValue(data interface{}) (string, error) {
+	switch v := data.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return formatNumber(v)
+	}
+}
+
+// upperSnake converts camelCase, kebab-case and other separated names to UPPER_SNAKE_CASE
+func upperSnake(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteByte('_')
+			}
+			continue
+		}
+
+		if unicode.IsUpper(r) && i > 0 && !strings.HasSuffix(b.String(), "_") {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// FromEnv builds a nested object from the environment variables whose name starts with prefix
+// (12-factor style config). With a separator, the prefix must be followed by it (unless the
+// prefix already ends with it), so prefix "APP" matches APP_DB_HOST but not APPLE_X or APPDATA.
+// An empty prefix matches every variable.
+// The prefix and that separator are stripped, and the rest of the name is lowercased and split
+// on separator to form the nesting:
+// with prefix "APP" and separator "_", APP_DB_HOST=h gives {"db": {"host": "h"}}.
+// Values are kept as strings, unless EnvInferTypes is given.
+// When a variable is both a value and a parent of others (APP_DB and APP_DB_HOST),
+// the nested values win.
+func FromEnv(prefix, separator string, opts ...EnvOption) Json {
+	return fromEnviron(os.Environ(), prefix, separator, opts...)
+}
+
+func fromEnviron(environ []string, prefix, separator string, opts ...EnvOption) Json {
+	var c envConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	sort.Strings(environ)
+
+	// with a separator, the prefix must be a whole segment: APP_ matches, APPLE doesn't.
+	// an empty prefix matches every name
+	if prefix != "" && separator != "" && !strings.HasSuffix(prefix, separator) {
+		prefix += separator
+	}
+
+	root := map[string]interface{}{}
+	for _, kv := range environ {
+		eq := strings.IndexByte(kv, '=')
+		if eq < 0 || !strings.HasPrefix(kv[:eq], prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(kv[:eq], prefix)
+
+		segments := []string{strings.ToLower(name)}
+		if separator != "" {
+			segments = strings.Split(segments[0], separator)
+		}
+
+		var path []string
+		for _, seg := range segments {
+			if seg != "" {
+				path = append(path, seg)
+			}
+		}
+		if len(path) == 0 {
+			continue
+		}
+
+		var value interface{} = kv[eq+1:]
+		if c.inferTypes {
+			value = inferEnvValue(kv[eq+1:])
+		}
+
+		m := root
+		for _, seg := range path[:len(path)-1] {
+			child, ok := m[seg].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				m[seg] = child
+			}
+			m = child
+		}
+
+		last := path[len(path)-1]
+		if _, isParent := m[last].(map[string]interface{}); !isParent {
+			m[last] = value
+		}
+	}
+
+	return Json{data: root, exists: true}
+}
+
+func inferEnvValue(s string) interface{} {
+	if s != strings.TrimSpace(s) {
+		return s
+	}
+
+	if v, err := decode([]byte(s)); err == nil {
+		switch v.(type) {
+		case json.Number, bool:
+			return v
+		}
+	}
+
+	return s
+}
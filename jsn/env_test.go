@@ -0,0 +1,117 @@
+package jsn
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpperSnake(t *testing.T) {
+	assert.Equal(t, "DB_HOST", upperSnake("dbHost"))
+	assert.Equal(t, "DB_HOST", upperSnake("db-host"))
+	assert.Equal(t, "DB_HOST", upperSnake("db host"))
+	assert.Equal(t, "DB_HOST", upperSnake("db_host"))
+	assert.Equal(t, "HTTP_SERVER", upperSnake("HTTPServer"))
+	assert.Equal(t, "PORT2_NAME", upperSnake("port2Name"))
+	assert.Equal(t, "X", upperSnake("-x-"))
+}
+
+func TestToEnvListFlat(t *testing.T) {
+	j, err := NewJson(`{"port": 8080, "host": "localhost", "debug": true, "ratio": 0.5, "empty": null}`)
+	require.NoError(t, err)
+
+	list, err := j.ToEnvList()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"debug=true", "empty=", "host=localhost", "port=8080", "ratio=0.5"}, list)
+
+	j, err = NewJson(`{"logLevel": "info", "max-conns": 10}`)
+	require.NoError(t, err)
+
+	list, err = j.ToEnvList(EnvUpperSnake())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"LOG_LEVEL=info", "MAX_CONNS=10"}, list)
+}
+
+func TestToEnvListNested(t *testing.T) {
+	j, err := NewJson(`{"app": "x", "db": {"host": "h", "replicaHosts": ["r1", "r2"], "opts": {}}}`)
+	require.NoError(t, err)
+
+	_, err = j.ToEnvList()
+	assert.EqualError(t, err, `key "db" holds a nested object`)
+
+	list, err := j.ToEnvList(EnvFlatten("_"), EnvUpperSnake())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"APP=x", "DB_HOST=h", "DB_REPLICA_HOSTS_0=r1", "DB_REPLICA_HOSTS_1=r2"}, list)
+
+	list, err = j.ToEnvList(EnvFlatten("."))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app=x", "db.host=h", "db.replicaHosts.0=r1", "db.replicaHosts.1=r2"}, list)
+
+	j, err = NewJson(`{"a-b": 1, "a_b": 2}`)
+	require.NoError(t, err)
+	_, err = j.ToEnvList(EnvUpperSnake())
+	assert.EqualError(t, err, `duplicate key "A_B"`)
+
+	_, err = j.K("a-b").ToEnvList()
+	assert.EqualError(t, err, "value is not an object")
+}
+
+func TestFromEnv(t *testing.T) {
+	environ := []string{
+		"APP_DB_HOST=localhost",
+		"APP_DB_PORT=5432",
+		"APP_DB_TLS_ENABLED=true",
+		"APP_NAME=svc",
+		"APP_RATIO=0.5",
+		"APP_WEIRD= 1",
+		"APP_=ignored",
+		"OTHER_DB_HOST=nope",
+		"APPLE_X=1",
+		"APPDATA=/data",
+		"PATH=/bin",
+	}
+
+	j := fromEnviron(environ, "APP", "_")
+	expected, err := NewJson(`{
+		"db": {"host": "localhost", "port": "5432", "tls": {"enabled": "true"}},
+		"name": "svc",
+		"ratio": "0.5",
+		"weird": " 1"
+	}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(j), j.Pretty())
+
+	j = fromEnviron(environ, "APP_", "_", EnvInferTypes())
+	assert.Equal(t, Int{5432, true}, j.K("db").K("port").Int())
+	assert.Equal(t, Bool{true, true}, j.K("db").K("tls").K("enabled").Bool())
+	assert.Equal(t, Float64{0.5, true}, j.K("ratio").Float64())
+	assert.Equal(t, String{" 1", true}, j.K("weird").String())
+	assert.Equal(t, String{"svc", true}, j.K("name").String())
+
+	j = fromEnviron(environ, "APP", "")
+	assert.True(t, j.Exists("le_x"), "without a separator, any name starting with the prefix matches")
+	assert.True(t, j.Exists("data"))
+
+	j = fromEnviron([]string{"DB_HOST=h", "PATH=/bin", "_X=1"}, "", "_")
+	expected, err = NewJson(`{"db": {"host": "h"}, "path": "/bin", "x": "1"}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(j), "an empty prefix matches every name: %s", j.Pretty())
+
+	j = fromEnviron([]string{"X__A=1", "X__A__B=2", "X__C__D=3", "X__C=4"}, "X", "__")
+	expected, err = NewJson(`{"a": {"b": "2"}, "c": {"d": "3"}}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(j), j.Pretty())
+}
+
+func TestFromOSEnv(t *testing.T) {
+	os.Setenv("JSNTEST_SERVER_HOST", "example.com")
+	os.Setenv("JSNTEST_SERVER_PORT", "443")
+	defer os.Unsetenv("JSNTEST_SERVER_HOST")
+	defer os.Unsetenv("JSNTEST_SERVER_PORT")
+
+	j := FromEnv("JSNTEST", "_", EnvInferTypes())
+	assert.Equal(t, "example.com", j.K("server").K("host").String().Value)
+	assert.Equal(t, 443, j.K("server").K("port").Int().Value)
+}
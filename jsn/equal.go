@@ -0,0 +1,328 @@
+package jsn
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Equal reports whether two Json values are deeply equal:
+// objects are compared key by key regardless of order, arrays element by element in order.
+// Numbers are compared by their exact decimal value, regardless of representation: 1 equals 1.0
+// and 1e0, and large IDs don't collide through float64 rounding. A float64 (as produced by a
+// decoder without UseNumber) has the value of its shortest decimal form, so 0.1 equals
+// json.Number("0.1"), while 9007199254740993 doesn't equal the float64 it rounds to.
+// A JSON null is not equal to an undefined Json, but two undefined values are equal.
+func (j Json) Equal(other Json) bool {
+	if j.exists != other.exists {
+		return false
+	}
+
+	return equalValues(j.data, other.data)
+}
+
+// QuickUnequal reports whether this Json is definitely not Equal to other, based only on
+// cheap checks that don't walk the documents: different Kinds, objects with a different
+// number of keys, arrays of different lengths, or different scalar values.
+// It's meant to short-circuit comparing huge documents.
+// false doesn't mean the values are equal, only that Equal is needed to tell.
+func (j Json) QuickUnequal(other Json) bool {
+	kind := j.Type()
+	if kind != other.Type() {
+		return true
+	}
+
+	switch kind {
+	case KindObject:
+		return len(j.data.(map[string]interface{})) != len(other.data.(map[string]interface{}))
+	case KindArray:
+		return len(j.data.([]interface{})) != len(other.data.([]interface{}))
+	default:
+		return !equalValues(j.data, other.data)
+	}
+}
+
+func equalValues(a, b interface{}) bool {
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case float64, json.Number:
+		return equalNumbers(a, b)
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equalValues(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			w, exists := bv[k]
+			if !exists || !equalValues(v, w) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func equalNumbers(a, b interface{}) bool {
+	ad, ok := toDecimal(a)
+	if !ok {
+		return false
+	}
+	bd, ok := toDecimal(b)
+
+	return ok && ad == bd
+}
+
+// decimal is the exact value of a JSON number: 0.digits * 10^point, negated if neg.
+// digits has no leading or trailing zeros, so equal numbers have equal decimals,
+// and is empty for 0
+type decimal struct {
+	neg    bool
+	digits string
+	point  int
+	// hugePoint holds point in base 10 instead when it's beyond maxPoint, and is empty otherwise,
+	// so every value has a single representation
+	hugePoint string
+}
+
+// maxPoint bounds decimal.point, leaving room for arithmetic on it without overflow
+const maxPoint = 1 << 30
+
+// toDecimal returns the exact value of a JSON number in either representation.
+// a float64 is taken as its shortest decimal form, the one it's written as by encoding/json
+func toDecimal(n interface{}) (decimal, bool) {
+	switch v := n.(type) {
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return decimal{}, false
+		}
+		return parseDecimal(strconv.FormatFloat(v, 'e', -1, 64))
+	case json.Number:
+		return parseDecimal(string(v))
+	default:
+		return decimal{}, false
+	}
+}
+
+// parseDecimal parses a number in the JSON syntax, or as written by strconv with format 'e'
+func parseDecimal(s string) (decimal, bool) {
+	var d decimal
+	if strings.HasPrefix(s, "-") {
+		d.neg = true
+		s = s[1:]
+	}
+
+	expText := "0"
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		expText, s = s[i+1:], s[:i]
+		unsigned := strings.TrimLeft(expText, "+-")
+		if len(expText)-len(unsigned) > 1 || unsigned == "" || strings.Trim(unsigned, "0123456789") != "" {
+			return decimal{}, false
+		}
+	}
+
+	whole, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, fraction = s[:i], s[i+1:]
+	}
+	all := whole + fraction
+	if whole == "" || strings.Trim(all, "0123456789") != "" {
+		return decimal{}, false
+	}
+
+	digits := strings.TrimLeft(all, "0")
+	shift := len(whole) - (len(all) - len(digits))
+	d.digits = strings.TrimRight(digits, "0")
+	if d.digits == "" {
+		return decimal{}, true
+	}
+
+	if exp, err := strconv.Atoi(expText); err == nil && exp >= -maxPoint && exp <= maxPoint {
+		if point := exp + shift; point >= -maxPoint && point <= maxPoint {
+			d.point = point
+			return d, true
+		}
+	}
+
+	// the exponent of a JSON number has no limit: compute point exactly
+	point, _ := new(big.Int).SetString(expText, 10)
+	point.Add(point, big.NewInt(int64(shift)))
+	if point.IsInt64() && point.Int64() >= -maxPoint && point.Int64() <= maxPoint {
+		d.point = int(point.Int64())
+	} else {
+		d.hugePoint = point.String()
+	}
+
+	return d, true
+}
+
+// exponent returns the signed exponent of d in scientific notation, with a single digit
+// before the decimal point, as written by encoding/json: "+21", "-7"
+func (d decimal) exponent() string {
+	exp := big.NewInt(int64(d.point))
+	if d.hugePoint != "" {
+		exp.SetString(d.hugePoint, 10)
+	}
+	exp.Sub(exp, big.NewInt(1))
+
+	if exp.Sign() < 0 {
+		return exp.String()
+	}
+	return "+" + exp.String()
+}
+
+// numberValue returns the float64 value of a JSON number in either representation
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// EqualOptions configures EqualWith. The zero value is strict, like Equal.
+type EqualOptions struct {
+	// UnorderedArrays compares arrays as multisets: every element must match a distinct
+	// element of the other array, in any position
+	UnorderedArrays bool
+	// IgnorePointers lists JSON Pointers (e.g. "/meta/updatedAt") whose values aren't compared,
+	// and may be missing on either side. In arrays compared with UnorderedArrays, element
+	// indexes are those of the receiver.
+	IgnorePointers []string
+	// FloatEpsilon, when positive, makes numbers equal when they differ by at most FloatEpsilon
+	FloatEpsilon float64
+	// IgnoreNullVsAbsent makes an object key holding null equal to the key being absent
+	IgnoreNullVsAbsent bool
+}
+
+type equalConfig struct {
+	EqualOptions
+	ignored map[string]bool
+}
+
+// EqualWith is like Equal, with the comparison relaxed by opts
+func (j Json) EqualWith(other Json, opts EqualOptions) bool {
+	c := equalConfig{EqualOptions: opts, ignored: map[string]bool{}}
+	for _, p := range opts.IgnorePointers {
+		c.ignored[p] = true
+	}
+
+	if c.ignored[""] {
+		return true
+	}
+	if j.exists != other.exists {
+		return false
+	}
+
+	return c.equal("", j.data, other.data)
+}
+
+func (c *equalConfig) equal(pointer string, a, b interface{}) bool {
+	if c.ignored[pointer] {
+		return true
+	}
+
+	switch av := a.(type) {
+	case float64, json.Number:
+		if c.FloatEpsilon <= 0 {
+			return equalNumbers(a, b)
+		}
+		af, aOk := numberValue(a)
+		bf, bOk := numberValue(b)
+		return aOk && bOk && math.Abs(af-bf) <= c.FloatEpsilon
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		if c.UnorderedArrays {
+			return c.equalUnordered(pointer, av, bv)
+		}
+		for i := range av {
+			if !c.equal(pointer+"/"+strconv.Itoa(i), av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range av {
+			if !c.equalMember(pointer+"/"+escapePointerToken(k), v, bv, k) {
+				return false
+			}
+		}
+		for k, w := range bv {
+			if _, exists := av[k]; !exists && !c.equalMember(pointer+"/"+escapePointerToken(k), w, av, k) {
+				return false
+			}
+		}
+		return true
+	default:
+		return equalValues(a, b)
+	}
+}
+
+// equalMember compares v with the value of key in m
+func (c *equalConfig) equalMember(pointer string, v interface{}, m map[string]interface{}, key string) bool {
+	if c.ignored[pointer] {
+		return true
+	}
+
+	w, exists := m[key]
+	if !exists {
+		return c.IgnoreNullVsAbsent && v == nil
+	}
+
+	return c.equal(pointer, v, w)
+}
+
+// equalUnordered matches every element of a with a distinct element of b.
+// matching is greedy: with FloatEpsilon, an element matching several others may
+// take one that a later element needed
+func (c *equalConfig) equalUnordered(pointer string, a, b []interface{}) bool {
+	used := make([]bool, len(b))
+
+	for i, e := range a {
+		found := false
+		for k, f := range b {
+			if !used[k] && c.equal(pointer+"/"+strconv.Itoa(i), e, f) {
+				used[k] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,222 @@
+package jsn
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeUseNumber(t *testing.T, s string) Json {
+	var data interface{}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	require.NoError(t, dec.Decode(&data))
+
+	return Json{data: data, exists: true}
+}
+
+func TestEqualAcrossNumberRepresentations(t *testing.T) {
+	doc := `{"id": 42, "price": 9.99, "tags": [1, 2.5, "x"], "nested": {"n": -3, "e": 1e3}}`
+
+	var data interface{}
+	require.NoError(t, json.Unmarshal([]byte(doc), &data))
+	withFloats := Json{data: data, exists: true}
+	withNumbers := decodeUseNumber(t, doc)
+
+	assert.IsType(t, json.Number(""), withNumbers.K("id").Raw())
+	assert.IsType(t, float64(0), withFloats.K("id").Raw())
+
+	assert.True(t, withFloats.Equal(withNumbers))
+	assert.True(t, withNumbers.Equal(withFloats))
+
+	changed := decodeUseNumber(t, strings.Replace(doc, "9.99", "9.98", 1))
+	assert.False(t, withFloats.Equal(changed))
+
+	big1 := decodeUseNumber(t, `12345678901234567`)
+	big2 := decodeUseNumber(t, `12345678901234568`)
+	assert.False(t, big1.Equal(big2))
+	assert.True(t, big1.Equal(decodeUseNumber(t, `12345678901234567`)))
+
+	assert.True(t, decodeUseNumber(t, `1`).Equal(decodeUseNumber(t, `1.0`)))
+	assert.True(t, decodeUseNumber(t, `-0`).Equal(decodeUseNumber(t, `0.0e5`)))
+	assert.True(t, decodeUseNumber(t, `1500`).Equal(decodeUseNumber(t, `1.5E3`)))
+	assert.True(t, decodeUseNumber(t, `0.001`).Equal(decodeUseNumber(t, `1e-3`)))
+	assert.False(t, decodeUseNumber(t, `-1`).Equal(decodeUseNumber(t, `1`)))
+}
+
+func TestEqualLargeNumbersIsTransitive(t *testing.T) {
+	a := decodeUseNumber(t, `9007199254740993`)
+	b := decodeUseNumber(t, `9007199254740993.0`)
+	c := decodeUseNumber(t, `9007199254740992`)
+
+	assert.True(t, a.Equal(b))
+	assert.False(t, b.Equal(c), "9007199254740993.0 must not round to 9007199254740992")
+	assert.False(t, a.Equal(c))
+
+	// a float64 is its shortest decimal form
+	var f interface{}
+	require.NoError(t, json.Unmarshal([]byte(`[9007199254740992, 0.1]`), &f))
+	floats := Json{data: f, exists: true}
+	assert.True(t, floats.I(0).Equal(c))
+	assert.False(t, floats.I(0).Equal(a))
+	assert.True(t, floats.I(1).Equal(decodeUseNumber(t, `0.10`)))
+
+	assert.False(t, Json{data: json.Number("abc"), exists: true}.Equal(Json{data: json.Number("abc"), exists: true}))
+}
+
+func TestEqualHugeExponents(t *testing.T) {
+	huge, err := NewJson(`[1e99999999999999999999, 1e-99999999999999999999]`)
+	require.NoError(t, err)
+	assert.True(t, huge.Equal(huge))
+	assert.True(t, huge.I(0).Equal(huge.I(0)))
+	assert.True(t, huge.I(1).Equal(huge.I(1)))
+	assert.False(t, huge.I(0).Equal(huge.I(1)))
+
+	assert.True(t, huge.I(0).Equal(decodeUseNumber(t, `10e99999999999999999998`)))
+	assert.True(t, huge.I(0).Equal(decodeUseNumber(t, `0.001E+100000000000000000002`)))
+	assert.False(t, huge.I(0).Equal(decodeUseNumber(t, `1e99999999999999999998`)))
+	assert.False(t, huge.I(0).Equal(decodeUseNumber(t, `-1e99999999999999999999`)))
+
+	// around the largest exponent kept in an int
+	assert.True(t, decodeUseNumber(t, `1e1073741824`).Equal(decodeUseNumber(t, `10e1073741823`)))
+	assert.True(t, decodeUseNumber(t, `1e1073741823`).Equal(decodeUseNumber(t, `0.1e1073741824`)))
+	assert.False(t, decodeUseNumber(t, `1e1073741824`).Equal(decodeUseNumber(t, `1e1073741823`)))
+
+	for _, number := range []string{"1e", "1e+", "1e+-5", "1e5.0", "1ee5"} {
+		n := Json{data: json.Number(number), exists: true}
+		assert.False(t, n.Equal(n), number)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a, err := NewJson(`{"a": 1, "b": [1, {"c": null}]}`)
+	require.NoError(t, err)
+	b, err := NewJson(`{"b": [1.0, {"c": null}], "a": 1}`)
+	require.NoError(t, err)
+
+	assert.True(t, a.Equal(b))
+	assert.False(t, a.Equal(b.K("b")))
+	assert.False(t, a.K("b").Equal(Json{data: []interface{}{1.0}, exists: true}))
+	assert.False(t, a.Equal(Json{data: map[string]interface{}{"a": 1.0, "x": nil}, exists: true}))
+	assert.False(t, Json{data: "1", exists: true}.Equal(Json{data: 1.0, exists: true}))
+	assert.False(t, Json{data: true, exists: true}.Equal(Json{data: false, exists: true}))
+}
+
+func TestEqualNullAndUndefined(t *testing.T) {
+	j, err := NewJson(`{"null": null, "zero": 0, "empty": ""}`)
+	require.NoError(t, err)
+
+	assert.True(t, Json{}.Equal(Json{}))
+	assert.True(t, j.K("missing").Equal(j.K("other")))
+	assert.True(t, j.K("null").Equal(Json{data: nil, exists: true}))
+	assert.False(t, j.K("null").Equal(j.K("missing")))
+	assert.False(t, j.K("missing").Equal(j.K("null")))
+	assert.False(t, j.K("null").Equal(j.K("zero")))
+	assert.False(t, j.K("null").Equal(j.K("empty")))
+
+	withNull, err := NewJson(`{"a": 1, "b": null}`)
+	require.NoError(t, err)
+	withoutB, err := NewJson(`{"a": 1}`)
+	require.NoError(t, err)
+	assert.False(t, withNull.Equal(withoutB))
+	assert.False(t, withoutB.Equal(withNull))
+}
+
+func TestQuickUnequal(t *testing.T) {
+	j, err := NewJson(`{
+		"obj": {"a": 1, "b": 2},
+		"obj2": {"a": 1, "c": 2},
+		"obj3": {"a": 1},
+		"arr": [1, 2],
+		"arr2": [2, 1],
+		"arr3": [1],
+		"num": 1,
+		"float": 1.0,
+		"num2": 2,
+		"str": "1",
+		"null": null
+	}`)
+	require.NoError(t, err)
+
+	unequal := [][2]string{
+		{"obj", "arr"},
+		{"obj", "obj3"},
+		{"arr", "arr3"},
+		{"num", "num2"},
+		{"num", "str"},
+		{"null", "num"},
+		{"null", "missing"},
+	}
+	for _, keys := range unequal {
+		a, b := j.K(keys[0]), j.K(keys[1])
+		assert.True(t, a.QuickUnequal(b), "%s vs %s", keys[0], keys[1])
+		assert.True(t, b.QuickUnequal(a), "%s vs %s", keys[1], keys[0])
+		assert.False(t, a.Equal(b))
+	}
+
+	// the same size or value: a deep comparison is needed
+	needsEqual := [][2]string{
+		{"obj", "obj2"},
+		{"arr", "arr2"},
+		{"obj", "obj"},
+		{"num", "float"},
+		{"missing", "other"},
+	}
+	for _, keys := range needsEqual {
+		assert.False(t, j.K(keys[0]).QuickUnequal(j.K(keys[1])), "%s vs %s", keys[0], keys[1])
+	}
+}
+
+func TestEqualWith(t *testing.T) {
+	a, err := NewJson(`{"id": 1, "tags": ["x", "y", "x"], "price": 9.99, "meta": {"updatedAt": "t1", "by": "a"}, "note": null}`)
+	require.NoError(t, err)
+
+	strict := EqualOptions{}
+	assert.True(t, a.EqualWith(a, strict))
+
+	reordered, err := NewJson(`{"id": 1, "tags": ["y", "x", "x"], "price": 9.99, "meta": {"updatedAt": "t1", "by": "a"}, "note": null}`)
+	require.NoError(t, err)
+	assert.False(t, a.EqualWith(reordered, strict))
+	assert.True(t, a.EqualWith(reordered, EqualOptions{UnorderedArrays: true}))
+
+	notPermutation, err := NewJson(`{"id": 1, "tags": ["y", "x", "y"], "price": 9.99, "meta": {"updatedAt": "t1", "by": "a"}, "note": null}`)
+	require.NoError(t, err)
+	assert.False(t, a.EqualWith(notPermutation, EqualOptions{UnorderedArrays: true}))
+
+	touched, err := NewJson(`{"id": 1, "tags": ["x", "y", "x"], "price": 9.99, "meta": {"updatedAt": "t2", "by": "a"}, "note": null}`)
+	require.NoError(t, err)
+	assert.False(t, a.EqualWith(touched, strict))
+	assert.True(t, a.EqualWith(touched, EqualOptions{IgnorePointers: []string{"/meta/updatedAt"}}))
+	assert.False(t, a.EqualWith(touched, EqualOptions{IgnorePointers: []string{"/meta/by"}}))
+
+	untouched, err := NewJson(`{"id": 1, "tags": ["x", "y", "x"], "price": 9.99, "meta": {"by": "a"}, "note": null}`)
+	require.NoError(t, err)
+	assert.True(t, a.EqualWith(untouched, EqualOptions{IgnorePointers: []string{"/meta/updatedAt"}}))
+	assert.True(t, untouched.EqualWith(a, EqualOptions{IgnorePointers: []string{"/meta/updatedAt"}}))
+
+	rounded, err := NewJson(`{"id": 1, "tags": ["x", "y", "x"], "price": 9.990001, "meta": {"updatedAt": "t1", "by": "a"}, "note": null}`)
+	require.NoError(t, err)
+	assert.False(t, a.EqualWith(rounded, strict))
+	assert.True(t, a.EqualWith(rounded, EqualOptions{FloatEpsilon: 1e-5}))
+	assert.False(t, a.EqualWith(rounded, EqualOptions{FloatEpsilon: 1e-7}))
+
+	noNote, err := NewJson(`{"id": 1, "tags": ["x", "y", "x"], "price": 9.99, "meta": {"updatedAt": "t1", "by": "a"}}`)
+	require.NoError(t, err)
+	assert.False(t, a.EqualWith(noNote, strict))
+	assert.True(t, a.EqualWith(noNote, EqualOptions{IgnoreNullVsAbsent: true}))
+	assert.True(t, noNote.EqualWith(a, EqualOptions{IgnoreNullVsAbsent: true}))
+
+	combined, err := NewJson(`{"id": 1.0000001, "tags": ["x", "x", "y"], "price": 9.99, "meta": {"updatedAt": "t9", "by": "a"}}`)
+	require.NoError(t, err)
+	all := EqualOptions{UnorderedArrays: true, IgnorePointers: []string{"/meta/updatedAt"}, FloatEpsilon: 1e-6, IgnoreNullVsAbsent: true}
+	assert.True(t, a.EqualWith(combined, all))
+	all.FloatEpsilon = 0
+	assert.False(t, a.EqualWith(combined, all))
+
+	assert.True(t, Json{}.EqualWith(Json{}, strict))
+	assert.False(t, a.EqualWith(Json{}, strict))
+	assert.True(t, a.EqualWith(Json{}, EqualOptions{IgnorePointers: []string{""}}))
+}
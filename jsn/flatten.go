@@ -0,0 +1,79 @@
+package jsn
+
+import "strconv"
+
+// FlattenOptions configures FlattenSep
+type FlattenOptions struct {
+	// Separator joins the keys of nested objects, "." if empty:
+	// {"a": {"b": 1}} gives "a.b" (or "a/b" with "/")
+	Separator string
+	// BracketIndexes writes array indexes in brackets, "a[0]", instead of as
+	// separated segments, "a.0"
+	BracketIndexes bool
+	// EmitEmpty includes empty objects and arrays as values, instead of dropping them
+	EmitEmpty bool
+}
+
+// Flatten returns every leaf (string, number, bool or null) of the document keyed
+// by its JSON Pointer: {"a": {"b": [1]}} gives {"/a/b/0": 1}.
+// Empty objects and arrays are dropped. A scalar gives a single entry under "".
+// Returns an empty map for an undefined Json.
+func (j Json) Flatten() map[string]Json {
+	flat := map[string]Json{}
+
+	j.forEachLeaf(func(pointer string, v Json) bool {
+		flat[pointer] = v
+		return true
+	})
+
+	return flat
+}
+
+// FlattenSep is like Flatten, but builds the keys in the format given by opts,
+// e.g. "a.b[0]" or "a/b/0", to match what tools like Consul KV or dotted config
+// loaders expect. Keys are not escaped: when keys contain the separator, several
+// paths may give the same flat key, and the last one in sorted order wins.
+func (j Json) FlattenSep(opts FlattenOptions) map[string]Json {
+	if opts.Separator == "" {
+		opts.Separator = "."
+	}
+
+	flat := map[string]Json{}
+	if j.exists {
+		opts.flatten(flat, "", j.data)
+	}
+
+	return flat
+}
+
+func (opts FlattenOptions) flatten(flat map[string]Json, prefix string, data interface{}) {
+	join := func(token string) string {
+		if prefix == "" {
+			return token
+		}
+		return prefix + opts.Separator + token
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && opts.EmitEmpty {
+			flat[prefix] = Json{data: v, exists: true}
+		}
+		for _, k := range sortedKeys(v) {
+			opts.flatten(flat, join(k), v[k])
+		}
+	case []interface{}:
+		if len(v) == 0 && opts.EmitEmpty {
+			flat[prefix] = Json{data: v, exists: true}
+		}
+		for i, e := range v {
+			if opts.BracketIndexes {
+				opts.flatten(flat, prefix+"["+strconv.Itoa(i)+"]", e)
+			} else {
+				opts.flatten(flat, join(strconv.Itoa(i)), e)
+			}
+		}
+	default:
+		flat[prefix] = Json{data: v, exists: true}
+	}
+}
@@ -0,0 +1,66 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func flatStrings(flat map[string]Json) map[string]string {
+	strs := make(map[string]string, len(flat))
+	for k, v := range flat {
+		strs[k] = v.Stringify()
+	}
+	return strs
+}
+
+func TestFlatten(t *testing.T) {
+	j, err := NewJson(`{"a": {"b": [1, {"c": "x"}], "e": {}}, "n": null, "k/y": true, "l": []}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"/a/b/0":   "1",
+		"/a/b/1/c": `"x"`,
+		"/n":       "null",
+		"/k~1y":    "true",
+	}, flatStrings(j.Flatten()))
+
+	assert.Equal(t, map[string]string{"": "1"}, flatStrings(j.K("a").K("b").I(0).Flatten()))
+	assert.Equal(t, map[string]Json{}, j.K("no").Flatten())
+}
+
+func TestFlattenSep(t *testing.T) {
+	j, err := NewJson(`{"db": {"hosts": ["a", "b"], "port": 5432, "opts": {}}, "matrix": [[1, 2]], "tags": []}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"db.hosts.0": `"a"`,
+		"db.hosts.1": `"b"`,
+		"db.port":    "5432",
+		"matrix.0.0": "1",
+		"matrix.0.1": "2",
+	}, flatStrings(j.FlattenSep(FlattenOptions{})))
+
+	assert.Equal(t, map[string]string{
+		"db.hosts[0]":  `"a"`,
+		"db.hosts[1]":  `"b"`,
+		"db.port":      "5432",
+		"db.opts":      "{}",
+		"matrix[0][0]": "1",
+		"matrix[0][1]": "2",
+		"tags":         "[]",
+	}, flatStrings(j.FlattenSep(FlattenOptions{BracketIndexes: true, EmitEmpty: true})))
+
+	assert.Equal(t, map[string]string{
+		"db/hosts/0": `"a"`,
+		"db/hosts/1": `"b"`,
+		"db/port":    "5432",
+		"matrix/0/0": "1",
+		"matrix/0/1": "2",
+	}, flatStrings(j.FlattenSep(FlattenOptions{Separator: "/"})))
+
+	assert.Equal(t, map[string]string{"[0]": "1", "[1]": "2"},
+		flatStrings(j.K("matrix").I(0).FlattenSep(FlattenOptions{BracketIndexes: true})))
+	assert.Equal(t, map[string]string{"": "[]"}, flatStrings(j.K("tags").FlattenSep(FlattenOptions{EmitEmpty: true})))
+}
@@ -0,0 +1,186 @@
+package jsn
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// explainMaxKeys is the number of keys Explain lists for an object
+const explainMaxKeys = 5
+
+const (
+	truncatedMarker     = "...(truncated)"
+	unmarshalableMarker = "<unmarshalable>"
+)
+
+// ToTable renders an array of objects as an aligned text table, one row per element,
+// for CLI output and debugging:
+//
+//	id  name    tags
+//	--  ------  ---------
+//	1   gopher  ["go"]
+//	2   ferris
+//
+// The columns are the given keys, in order, or if none are given, the union of the
+// keys of all the elements, sorted. Strings are printed as is, and other values as
+// compact JSON. Missing values, and all the cells of elements that aren't objects,
+// are left empty. Columns are separated by two spaces, and trailing spaces are trimmed.
+// Returns an error if this isn't an array.
+func (j Json) ToTable(columns ...string) (string, error) {
+	a, ok := j.asArray()
+	if !ok {
+		return "", errors.New("value is not an array")
+	}
+
+	if len(columns) == 0 {
+		union := map[string]bool{}
+		for _, e := range a {
+			if m, ok := e.(map[string]interface{}); ok {
+				for k := range m {
+					union[k] = true
+				}
+			}
+		}
+		for k := range union {
+			columns = append(columns, k)
+		}
+		sort.Strings(columns)
+	}
+
+	rows := make([][]string, 0, len(a)+2)
+	rows = append(rows, columns)
+	rows = append(rows, make([]string, len(columns)))
+	for _, e := range a {
+		element := Json{data: e, exists: true}
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = cellString(element.Get(c))
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(columns))
+	for _, row := range rows {
+		for i, cell := range row {
+			if w := utf8.RuneCountInString(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	for i, w := range widths {
+		rows[1][i] = strings.Repeat("-", w)
+	}
+
+	lines := make([]string, len(rows))
+	for r, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			line.WriteString(cell)
+			line.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		lines[r] = strings.TrimRight(line.String(), " ")
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func cellString(v Json) string {
+	if s := v.String(); s.IsValid {
+		return s.Value
+	}
+	if v.Undefined() {
+		return ""
+	}
+
+	return v.Stringify()
+}
+
+// Explain returns a short description of the value, for logs and error messages,
+// e.g. to say what was found when an accessor returned an invalid value:
+//   - "undefined", "null"
+//   - "bool true", "number 42"
+//   - "string of length 12" (in runes), "empty string"
+//   - "array of 5 numbers" (all elements of the same Kind), "array of 3 mixed values", "empty array"
+//   - "object with 3 keys (id, name, tags)" (sorted, at most 5 listed then "..."), "empty object"
+func (j Json) Explain() string {
+	switch j.Type() {
+	case KindInvalid:
+		return "undefined"
+	case KindNull:
+		return "null"
+	case KindBool, KindNumber:
+		return j.Type().String() + " " + j.Stringify()
+	case KindString:
+		n := utf8.RuneCountInString(j.data.(string))
+		if n == 0 {
+			return "empty string"
+		}
+		return fmt.Sprintf("string of length %d", n)
+	case KindArray:
+		a := j.data.([]interface{})
+		if len(a) == 0 {
+			return "empty array"
+		}
+
+		kind := Json{data: a[0], exists: true}.Type()
+		for _, e := range a[1:] {
+			if (Json{data: e, exists: true}).Type() != kind {
+				return fmt.Sprintf("array of %d mixed values", len(a))
+			}
+		}
+		return fmt.Sprintf("array of %d %s", len(a), plural(len(a), kind.String()))
+	case KindObject:
+		keys := sortedKeys(j.data.(map[string]interface{}))
+		if len(keys) == 0 {
+			return "empty object"
+		}
+
+		listed := keys
+		if len(keys) > explainMaxKeys {
+			listed = append(keys[:explainMaxKeys:explainMaxKeys], "...")
+		}
+		return fmt.Sprintf("object with %d %s (%s)", len(keys), plural(len(keys), "key"), strings.Join(listed, ", "))
+	default:
+		return fmt.Sprintf("unsupported %T", j.data)
+	}
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+// LogString returns the value as compact JSON on a single line, safe for log messages:
+// if the JSON is longer than maxBytes, it's cut and ends with "...(truncated)", the whole
+// string (marker included) being maxBytes long. The cut never splits a UTF-8 character,
+// so it may be a few bytes shorter. When maxBytes is shorter than the marker, only the
+// marker's first maxBytes bytes are returned ("..." for 3). A maxBytes of 0 or less means no limit.
+// If the value can't be marshaled, "<unmarshalable>" is returned instead of an empty string.
+func (j Json) LogString(maxBytes int) string {
+	s, err := j.Marshal()
+	if err != nil {
+		return unmarshalableMarker
+	}
+	if maxBytes <= 0 || len(s) <= maxBytes {
+		return s
+	}
+
+	if maxBytes < len(truncatedMarker) {
+		return truncatedMarker[:maxBytes]
+	}
+
+	cut := maxBytes - len(truncatedMarker)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+
+	return s[:cut] + truncatedMarker
+}
@@ -0,0 +1,106 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTable(t *testing.T) {
+	j, err := NewJson(`[
+		{"id": 1, "name": "gopher", "tags": ["go"]},
+		{"id": 22, "name": "ferris", "active": false},
+		"not an object",
+		{"id": 333, "name": "Gö", "tags": null}
+	]`)
+	require.NoError(t, err)
+
+	table, err := j.ToTable()
+	require.NoError(t, err)
+	assert.Equal(t, ""+
+		"active  id   name    tags\n"+
+		"------  ---  ------  ------\n"+
+		"        1    gopher  [\"go\"]\n"+
+		"false   22   ferris\n"+
+		"\n"+
+		"        333  Gö      null", table)
+
+	table, err = j.ToTable("name", "id")
+	require.NoError(t, err)
+	assert.Equal(t, ""+
+		"name    id\n"+
+		"------  ---\n"+
+		"gopher  1\n"+
+		"ferris  22\n"+
+		"\n"+
+		"Gö      333", table)
+
+	empty, err := NewJson(`[]`)
+	require.NoError(t, err)
+	table, err = empty.ToTable("a")
+	require.NoError(t, err)
+	assert.Equal(t, "a\n-", table)
+
+	_, err = j.I(0).ToTable()
+	assert.EqualError(t, err, "value is not an array")
+}
+
+func TestExplain(t *testing.T) {
+	j, err := NewJson(`{
+		"id": 7,
+		"name": "Gö gopher!!",
+		"empty": "",
+		"flag": true,
+		"nothing": null,
+		"nums": [1, 2.5, 3, 4, 5],
+		"one": ["x"],
+		"mixed": [1, "a", null],
+		"none": [],
+		"obj": {"tags": [], "id": 1, "name": "x"},
+		"single": {"k": 1},
+		"wide": {"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6},
+		"eo": {}
+	}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "undefined", j.K("no").Explain())
+	assert.Equal(t, "null", j.K("nothing").Explain())
+	assert.Equal(t, "bool true", j.K("flag").Explain())
+	assert.Equal(t, "number 7", j.K("id").Explain())
+	assert.Equal(t, "string of length 11", j.K("name").Explain())
+	assert.Equal(t, "empty string", j.K("empty").Explain())
+	assert.Equal(t, "array of 5 numbers", j.K("nums").Explain())
+	assert.Equal(t, "array of 1 string", j.K("one").Explain())
+	assert.Equal(t, "array of 3 mixed values", j.K("mixed").Explain())
+	assert.Equal(t, "empty array", j.K("none").Explain())
+	assert.Equal(t, "object with 3 keys (id, name, tags)", j.K("obj").Explain())
+	assert.Equal(t, "object with 1 key (k)", j.K("single").Explain())
+	assert.Equal(t, "object with 6 keys (a, b, c, d, e, ...)", j.K("wide").Explain())
+	assert.Equal(t, "empty object", j.K("eo").Explain())
+}
+
+func TestLogString(t *testing.T) {
+	j, err := NewJson(`{"msg": "héllo wörld", "n": [1, 2, 3]}`)
+	require.NoError(t, err)
+
+	full := `{"msg":"héllo wörld","n":[1,2,3]}`
+	assert.Equal(t, full, j.LogString(0))
+	assert.Equal(t, full, j.LogString(len(full)))
+
+	short := j.LogString(24)
+	assert.Equal(t, `{"msg":"h...(truncated)`, short)
+	assert.Len(t, short, 23, "must not split the 2-bytes é")
+
+	assert.Equal(t, `{"msg":"hé...(truncated)`, j.LogString(25))
+	for maxBytes := 1; maxBytes < len(full); maxBytes++ {
+		assert.True(t, len(j.LogString(maxBytes)) <= maxBytes, "maxBytes %d", maxBytes)
+	}
+	assert.Equal(t, "...", j.LogString(3))
+	assert.Equal(t, "...(truncated)", j.LogString(14))
+
+	bad := Json{data: map[string]interface{}{"c": make(chan int)}, exists: true}
+	assert.Equal(t, "<unmarshalable>", bad.LogString(100))
+
+	assert.Equal(t, "null", Json{}.LogString(10))
+}
@@ -0,0 +1,87 @@
+package jsn
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// NewJsonInterned is like NewJson, but deduplicates the parsed document: structurally
+// identical subtrees (objects, arrays and strings) are stored once and shared by every
+// location holding them. For large repetitive payloads, e.g. denormalized records repeating
+// the same nested objects, this reduces the memory the document holds on to.
+// The tradeoff is time: every node is hashed up front, so parsing is slower and allocates
+// more while it runs; it only pays off for documents kept around and with enough repetition.
+// Reading works exactly as with NewJson, and Equal is unaffected (numbers keep their
+// representation, so 1 and 1.0 are not merged).
+// Since subtrees are shared, the document must not be modified in place (Set, SetPath,
+// Delete, MergePatchInto...): a change would show at every location sharing the subtree.
+// Modify a Clone instead.
+func NewJsonInterned(src interface{}) (Json, error) {
+	j, err := NewJson(src)
+	if err != nil {
+		return Json{}, err
+	}
+
+	in := interner{table: map[string]internedValue{}}
+	j.data, _ = in.intern(j.data)
+
+	return j, nil
+}
+
+type internedValue struct {
+	id   int
+	data interface{}
+}
+
+type interner struct {
+	table map[string]internedValue
+}
+
+// intern returns the shared instance of data, and its id.
+// the maps and slices of data are updated in place to point to shared instances
+func (in *interner) intern(data interface{}) (interface{}, int) {
+	var sig strings.Builder
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		sig.WriteByte('{')
+		for _, k := range sortedKeys(v) {
+			e, id := in.intern(v[k])
+			v[k] = e
+			sig.WriteString(strconv.Quote(k))
+			sig.WriteByte(':')
+			sig.WriteString(strconv.Itoa(id))
+			sig.WriteByte(',')
+		}
+	case []interface{}:
+		sig.WriteByte('[')
+		for i, e := range v {
+			e, id := in.intern(e)
+			v[i] = e
+			sig.WriteString(strconv.Itoa(id))
+			sig.WriteByte(',')
+		}
+	case string:
+		sig.WriteByte('s')
+		sig.WriteString(v)
+	case json.Number:
+		sig.WriteByte('n')
+		sig.WriteString(string(v))
+	case bool:
+		sig.WriteString(strconv.FormatBool(v))
+	case nil:
+		sig.WriteString("null")
+	default:
+		return data, -1
+	}
+
+	key := sig.String()
+	if shared, ok := in.table[key]; ok {
+		return shared.data, shared.id
+	}
+
+	id := len(in.table)
+	in.table[key] = internedValue{id: id, data: data}
+	return data, id
+}
@@ -0,0 +1,78 @@
+package jsn
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func repetitiveFixture(records int) string {
+	items := make([]string, records)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id": %d, "status": "active", "tags": ["alpha", "beta"],
+			"owner": {"team": "platform", "region": "eu-west-1", "contacts": ["ops@example.com", "dev@example.com"]}}`, i)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func TestNewJsonInterned(t *testing.T) {
+	doc := repetitiveFixture(3)
+
+	plain, err := NewJson(doc)
+	require.NoError(t, err)
+	interned, err := NewJsonInterned(doc)
+	require.NoError(t, err)
+
+	assert.True(t, plain.Equal(interned))
+	assert.Equal(t, plain.Stringify(), interned.Stringify())
+	assert.Equal(t, 2, interned.I(2).K("id").Int().Value)
+
+	// identical subtrees are shared, distinct ones aren't
+	owner0 := interned.I(0).K("owner").Raw().(map[string]interface{})
+	owner2 := interned.I(2).K("owner").Raw().(map[string]interface{})
+	assert.Equal(t, fmt.Sprintf("%p", owner0), fmt.Sprintf("%p", owner2))
+	assert.NotEqual(t, fmt.Sprintf("%p", interned.I(0).Raw()), fmt.Sprintf("%p", interned.I(1).Raw()))
+
+	numbers, err := NewJsonInterned(`[1, 1.0, {"a": 1}, {"a": 1.0}, "1", true, null]`)
+	require.NoError(t, err)
+	assert.Equal(t, `[1,1.0,{"a":1},{"a":1.0},"1",true,null]`, numbers.Stringify())
+
+	_, err = NewJsonInterned(`{broken`)
+	assert.Error(t, err)
+}
+
+func benchmarkRetained(b *testing.B, parse func(string) (Json, error)) {
+	doc := repetitiveFixture(1000)
+	b.ReportAllocs()
+
+	var retained uint64
+	for i := 0; i < b.N; i++ {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		j, err := parse(doc)
+		require.NoError(b, err)
+
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		runtime.KeepAlive(j)
+		if after.HeapAlloc > before.HeapAlloc {
+			retained += after.HeapAlloc - before.HeapAlloc
+		}
+	}
+
+	b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+}
+
+func BenchmarkNewJsonRepetitive(b *testing.B) {
+	benchmarkRetained(b, func(doc string) (Json, error) { return NewJson(doc) })
+}
+
+func BenchmarkNewJsonInternedRepetitive(b *testing.B) {
+	benchmarkRetained(b, func(doc string) (Json, error) { return NewJsonInterned(doc) })
+}
@@ -4,11 +4,17 @@
 package jsn
 
 import (
+	"bufio"
+	"bytes"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // String carries a string .Value if .IsValid
@@ -29,6 +35,18 @@ type Int64 struct {
 	IsValid bool
 }
 
+// Uint carries a uint .Value if .IsValid
+type Uint struct {
+	Value   uint
+	IsValid bool
+}
+
+// Uint64 carries a uint64 .Value if .IsValid
+type Uint64 struct {
+	Value   uint64
+	IsValid bool
+}
+
 // Float64 carries a float64 .Value if .IsValid
 type Float64 struct {
 	Value   float64
@@ -57,7 +75,7 @@ func (a Array) Elements() []Json {
 
 	values := make([]Json, len(a.elements))
 	for i := 0; i < len(a.elements); i++ {
-		values[i] = Json{a.elements[i], true}
+		values[i] = Json{data: a.elements[i], exists: true}
 	}
 
 	return values
@@ -65,26 +83,41 @@ func (a Array) Elements() []Json {
 
 /////////////////
 
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
 // Json represents any valid JSON: map, array, bool, number, string, or null.
 // it's an opaque structure, and the data can be accessed via it's methods
 type Json struct {
 	data   interface{}
 	exists bool
+	obs    *observer
 }
 
 // NewJson constructs a new Json object from a wide variety of sources:
 // - a JSON string from a string, []byte, io.Reader
 // - any interface{} that is json.Marshal-able
+//
+// A leading UTF-8 byte order mark is skipped in JSON strings, as are the JSON
+// whitespace characters (space, tab, CR, LF) around the value. Any other leading or
+// trailing bytes are an error, for an io.Reader too: it's read to EOF, and must hold
+// a single value (use NewStream for a sequence of values).
+//
+// Numbers are kept as json.Number rather than float64, so large integers like IDs
+// keep their exact value: Raw() returns json.Number for them.
 func NewJson(src interface{}) (js Json, err error) {
 	var data interface{}
 
 	switch src.(type) {
 	case []byte:
-		err = json.Unmarshal(src.([]byte), &data)
+		data, err = decode(bytes.TrimPrefix(src.([]byte), utf8BOM))
 	case string:
-		err = json.Unmarshal([]byte(src.(string)), &data)
+		data, err = decode([]byte(strings.TrimPrefix(src.(string), string(utf8BOM))))
 	case io.Reader:
-		err = json.NewDecoder(src.(io.Reader)).Decode(&data)
+		reader := bufio.NewReader(src.(io.Reader))
+		if prefix, _ := reader.Peek(len(utf8BOM)); bytes.Equal(prefix, utf8BOM) {
+			reader.Discard(len(utf8BOM))
+		}
+		data, err = decodeReader(reader)
 	default:
 		var bytes []byte
 		bytes, err = json.Marshal(src)
@@ -92,11 +125,11 @@ func NewJson(src interface{}) (js Json, err error) {
 			break
 		}
 
-		err = json.Unmarshal(bytes, &data)
+		data, err = decode(bytes)
 	}
 
 	if err == nil {
-		js = Json{data, true}
+		js = Json{data: data, exists: true}
 	}
 
 	return
@@ -137,17 +170,31 @@ func (j Json) Exists(key string) bool {
 	return exists
 }
 
+// Has returns true if this is a Json map, and the key exists with a usable value,
+// i.e. one that is not empty per IsEmpty (null, "", [] or {}).
+// 0 and false are usable values.
+func (j Json) Has(key string) bool {
+	m, ok := j.asMap()
+
+	if !ok {
+		return false
+	}
+
+	v, exists := m[key]
+	return exists && !Json{data: v, exists: true}.IsEmpty()
+}
+
 // Get returns the nested Json value under a key.
 // returns an empty Json{} if key doesn't exists, or if this isn't a map
 func (j Json) Get(key string) Json {
 	m, ok := j.asMap()
 
 	if !ok {
-		return Json{}
+		return j.keyChild(key, nil, false).observe()
 	}
 
 	v, exists := m[key]
-	return Json{v, exists}
+	return j.keyChild(key, v, exists).observe()
 }
 
 // K is a shortcut for Get()
@@ -161,14 +208,44 @@ func (j Json) I(index int) Json {
 	a, ok := j.asArray()
 
 	if !ok {
-		return Json{}
+		return j.indexChild(index, nil, false).observe()
 	}
 
 	if index < 0 || index > len(a)-1 {
-		return Json{}
+		return j.indexChild(index, nil, false).observe()
+	}
+
+	return j.indexChild(index, a[index], true).observe()
+}
+
+// GetE is like Get, but returns an error instead of an undefined Json:
+// "value is not an object" if this isn't a map, or "key \"foo\" not found".
+// A key holding null is found: check the result with Null().
+func (j Json) GetE(key string) (Json, error) {
+	v := j.Get(key)
+	if v.exists {
+		return v, nil
 	}
 
-	return Json{a[index], true}
+	if _, ok := j.asMap(); !ok {
+		return Json{}, errors.New("value is not an object")
+	}
+	return Json{}, fmt.Errorf("key %q not found", key)
+}
+
+// IE is like I, but returns an error instead of an undefined Json:
+// "value is not an array" if this isn't an array, or "index 3 out of range (len 2)".
+func (j Json) IE(index int) (Json, error) {
+	v := j.I(index)
+	if v.exists {
+		return v, nil
+	}
+
+	a, ok := j.asArray()
+	if !ok {
+		return Json{}, errors.New("value is not an array")
+	}
+	return Json{}, fmt.Errorf("index %d out of range (len %d)", index, len(a))
 }
 
 // IterMap calls the callback for every kay-value pair in a JSON map,
@@ -184,7 +261,7 @@ func (j Json) IterMap(f func(key string, value Json) bool) int {
 	count := 0
 	for k, v := range m {
 		count++
-		if !f(k, Json{v, true}) {
+		if !f(k, j.keyChild(k, v, true)) {
 			break
 		}
 	}
@@ -192,6 +269,62 @@ func (j Json) IterMap(f func(key string, value Json) bool) int {
 	return count
 }
 
+// IterMapSorted is like IterMap, but visits the keys in ascending order
+func (j Json) IterMapSorted(f func(key string, value Json) bool) int {
+	m, ok := j.asMap()
+	if !ok {
+		return 0
+	}
+
+	count := 0
+	for _, k := range sortedKeys(m) {
+		count++
+		if !f(k, j.keyChild(k, m[k], true)) {
+			break
+		}
+	}
+
+	return count
+}
+
+// IterArray calls the callback for every element of a JSON array, with its index,
+// and returns the number of elements iterated.
+// Unlike Array().Elements(), it doesn't allocate a slice of Json.
+// If it's not an array value, this method will do nothing.
+// Caller can break the loop by returning false from the callback.
+func (j Json) IterArray(f func(index int, value Json) bool) int {
+	a, ok := j.asArray()
+	if !ok {
+		return 0
+	}
+
+	count := 0
+	for i, v := range a {
+		count++
+		if !f(i, j.indexChild(i, v, true)) {
+			break
+		}
+	}
+
+	return count
+}
+
+// ForEachChild calls the callback for every immediate child of an object or an array,
+// so generic code can handle both with one loop, and returns the number of children iterated.
+// keyOrIndex is the key for object members (visited in ascending key order),
+// and the decimal index (e.g. "0") for array elements.
+// If it's neither an object nor an array, this method will do nothing.
+// Caller can break the loop by returning false from the callback.
+func (j Json) ForEachChild(f func(keyOrIndex string, v Json) bool) int {
+	if _, ok := j.asArray(); ok {
+		return j.IterArray(func(i int, v Json) bool {
+			return f(strconv.Itoa(i), v)
+		})
+	}
+
+	return j.IterMapSorted(f)
+}
+
 // Undefined returns true if this Json is undefined.
 // in example result of .Get(key) with a key that doesn't exist.
 // like in JS, Null() != Undefined().
@@ -205,11 +338,52 @@ func (j Json) Null() bool {
 	return j.exists && j.data == nil
 }
 
+// IsZero returns true for an undefined Json or a JSON null.
+// It lets a Json struct field tagged `json:",omitzero"` be left out when marshaling
+// the struct (Go 1.24+). `omitempty` has no effect on struct types such as Json,
+// so without omitzero an undefined or null field is marshaled as null.
+func (j Json) IsZero() bool {
+	return j.NullOrUndefined()
+}
+
 // NullOrUndefined returns (.Null() || .Undefined())
 func (j Json) NullOrUndefined() bool {
 	return j.data == nil
 }
 
+// IsEmpty returns true if this is undefined, null, an empty string, an empty array
+// or an empty object. Numbers and bools are never empty, even 0 or false.
+func (j Json) IsEmpty() bool {
+	switch v := j.data.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// Len returns the number of elements of an array, the number of keys of an object,
+// or the number of characters (runes, not bytes) of a string.
+// returns 0 for numbers, bools, null and undefined values.
+func (j Json) Len() int {
+	switch v := j.data.(type) {
+	case []interface{}:
+		return len(v)
+	case map[string]interface{}:
+		return len(v)
+	case string:
+		return utf8.RuneCountInString(v)
+	default:
+		return 0
+	}
+}
+
 func (j Json) String() String {
 	if !j.exists {
 		return String{}
@@ -221,6 +395,30 @@ func (j Json) String() String {
 	return String{}
 }
 
+// AsString returns a textual representation of any value, unlike String() which is only
+// valid for JSON strings: a string is returned as is (unquoted), a number as written in the
+// document (or, if it was decoded as a float64, formatted with strconv without an exponent),
+// a bool as "true" or "false", and an array or an object as compact JSON (see Stringify).
+// null gives "null", and undefined gives an empty string.
+func (j Json) AsString() string {
+	if !j.exists {
+		return ""
+	}
+
+	switch v := j.data.(type) {
+	case string:
+		return v
+	case json.Number:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return j.Stringify()
+	}
+}
+
 func (j Json) Int64() Int64 {
 	if !j.exists {
 		return Int64{}
@@ -230,8 +428,16 @@ func (j Json) Int64() Int64 {
 	case float64:
 		return Int64{int64(j.data.(float64)), true}
 	case json.Number:
-		v, err := j.data.(json.Number).Int64()
-		return Int64{v, err == nil}
+		if v, err := j.data.(json.Number).Int64(); err == nil {
+			return Int64{v, true}
+		}
+
+		// not an integer literal, e.g. "2.07": truncate like for float64
+		f, err := j.data.(json.Number).Float64()
+		if err != nil || f < math.MinInt64 || f >= math.MaxInt64 {
+			return Int64{}
+		}
+		return Int64{int64(f), true}
 	default:
 		return Int64{}
 	}
@@ -243,6 +449,44 @@ func (j Json) Int() Int {
 	return Int{int(v.Value), v.IsValid}
 }
 
+// Uint64 returns the number as a uint64, exactly for integer literals up to math.MaxUint64
+// (beyond the range of Int64), truncating other numbers.
+// Negative numbers are not valid.
+func (j Json) Uint64() Uint64 {
+	if !j.exists {
+		return Uint64{}
+	}
+
+	var f float64
+	switch v := j.data.(type) {
+	case float64:
+		f = v
+	case json.Number:
+		if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return Uint64{u, true}
+		}
+
+		// not an integer literal, e.g. "2.07": truncate like for float64
+		var err error
+		if f, err = v.Float64(); err != nil {
+			return Uint64{}
+		}
+	default:
+		return Uint64{}
+	}
+
+	if f < 0 || f >= math.MaxUint64 {
+		return Uint64{}
+	}
+	return Uint64{uint64(f), true}
+}
+
+func (j Json) Uint() Uint {
+	v := j.Uint64()
+
+	return Uint{uint(v.Value), v.IsValid}
+}
+
 func (j Json) Float64() Float64 {
 	if !j.exists {
 		return Float64{}
@@ -272,12 +516,65 @@ func (j Json) Bool() Bool {
 	}
 }
 
+// StringOr returns the string value, or def if this isn't a string (including null and undefined)
+func (j Json) StringOr(def string) string {
+	if v := j.String(); v.IsValid {
+		return v.Value
+	}
+	return def
+}
+
+// IntOr returns the number as an int (truncated like Int), or def if this isn't a number
+func (j Json) IntOr(def int) int {
+	if v := j.Int(); v.IsValid {
+		return v.Value
+	}
+	return def
+}
+
+// Int64Or returns the number as an int64 (truncated like Int64), or def if this isn't a number
+func (j Json) Int64Or(def int64) int64 {
+	if v := j.Int64(); v.IsValid {
+		return v.Value
+	}
+	return def
+}
+
+// Float64Or returns the number as a float64, or def if this isn't a number
+func (j Json) Float64Or(def float64) float64 {
+	if v := j.Float64(); v.IsValid {
+		return v.Value
+	}
+	return def
+}
+
+// BoolOr returns the boolean value, or def if this isn't a boolean
+func (j Json) BoolOr(def bool) bool {
+	if v := j.Bool(); v.IsValid {
+		return v.Value
+	}
+	return def
+}
+
 func (j Json) Array() Array {
 	a, ok := j.asArray()
 
 	return Array{a, ok}
 }
 
+// FirstArray returns the Array under the first of keys holding an array.
+// A key whose value isn't an array (e.g. null or a string) is skipped in favor of the next keys.
+// Returns an invalid, empty Array if no key qualifies, or if this isn't a map.
+func (j Json) FirstArray(keys ...string) Array {
+	for _, key := range keys {
+		if a := j.K(key).Array(); a.IsValid {
+			return a
+		}
+	}
+
+	return Array{}
+}
+
 func (j Json) Raw() interface{} {
 	return j.data
 }
@@ -296,6 +593,19 @@ func (j Json) Unmarshal(target interface{}) error {
 	return json.Unmarshal(bytes, target)
 }
 
+// DecodeWith passes the value, re-serialized to JSON bytes first (like MarshalJSON, so an
+// undefined Json gives null), to a decoding function, to plug in decoders this package
+// doesn't depend on, e.g. a protobuf JSON unmarshaler or a custom numeric decoder.
+// Returns the error of the serialization or of fn.
+func (j Json) DecodeWith(fn func(json.RawMessage) error) error {
+	raw, err := j.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	return fn(raw)
+}
+
 func (j Json) Marshal() (string, error) {
 	buf, err := json.Marshal(j)
 	if err != nil {
@@ -339,6 +649,61 @@ func (j Json) Stringify() string {
 	return string(buf)
 }
 
+// StringifyRaw is like Stringify, but without escaping <, > and & as \u003c, \u003e and \u0026,
+// for JSON meant to be read by humans or embedded in another document.
+// Stringify keeps escaping them, so the output is safe to embed in HTML.
+func (j Json) StringifyRaw() string {
+	return j.StringifyRawIndent("", "")
+}
+
+// StringifyRawIndent is like StringifyIndent, without escaping <, > and & (see StringifyRaw)
+func (j Json) StringifyRawIndent(prefix, indent string) string {
+	var data interface{}
+	if j.exists {
+		data = j.data
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent(prefix, indent)
+	if err := enc.Encode(data); err != nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// WriteTo implements io.WriterTo: it encodes the value as compact JSON straight into w,
+// followed by a newline (as json.Encoder does), without building a string first,
+// e.g. to send a big document as an HTTP response.
+// Returns the number of bytes written, and the error of the encoding or of w.
+func (j Json) WriteTo(w io.Writer) (int64, error) {
+	return j.WriteIndentedTo(w, "", "")
+}
+
+// WriteIndentedTo is like WriteTo, with the indentation of MarshalIndent
+func (j Json) WriteIndentedTo(w io.Writer, prefix, indent string) (int64, error) {
+	cw := &countingWriter{w: w}
+	enc := json.NewEncoder(cw)
+	enc.SetIndent(prefix, indent)
+
+	err := enc.Encode(j)
+	return cw.n, err
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
 // implementing json.Marshaler interface
 func (j Json) MarshalJSON() ([]byte, error) {
 	if j.exists {
@@ -348,35 +713,99 @@ func (j Json) MarshalJSON() ([]byte, error) {
 	}
 }
 
-// implementing the json.Unmarshler interface
+// implementing the json.Unmarshler interface.
+// numbers are kept as json.Number (see decode), so a Json struct field holds
+// large integers without losing precision even when the struct is decoded by json.Unmarshal
 func (j *Json) UnmarshalJSON(data []byte) error {
-	err := json.Unmarshal(data, &j.data)
+	v, err := decode(data)
 
+	j.data = v
 	j.exists = (err == nil)
 	return err
 }
 
-// implementing the sql.Scanner interface
+// decode parses exactly one JSON value, keeping numbers as json.Number
+// instead of float64 to preserve their precision
+func decode(data []byte) (interface{}, error) {
+	return decodeReader(bytes.NewReader(data))
+}
+
+// decodeReader decodes the single JSON value r holds, failing on anything but whitespace after it
+func decodeReader(r io.Reader) (interface{}, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid data after top-level value")
+	}
+
+	return v, nil
+}
+
+// implementing the sql.Scanner interface.
+// src can be []byte or string, as SQL drivers disagree on the type of JSON columns,
+// or an io.Reader. A nil src (SQL NULL) gives an undefined Json, not a JSON null.
 func (j *Json) Scan(src interface{}) error {
-	switch src.(type) {
+	switch v := src.(type) {
 	case []byte:
-		return j.UnmarshalJSON(src.([]byte))
+		return j.UnmarshalJSON(v)
+	case string:
+		return j.UnmarshalJSON([]byte(v))
+	case io.Reader:
+		parsed, err := NewJson(v)
+		*j = parsed
+		return err
+	case nil:
+		*j = Json{}
+		return nil
 	default:
-		return fmt.Errorf("unsupprted src type")
+		return fmt.Errorf("unsupported src type %T", src)
 	}
 }
 
-// implementing the sql/driver.Valuer interface
+// implementing the sql/driver.Valuer interface.
+// An undefined Json gives nil, stored as SQL NULL (and scanned back as undefined),
+// while a JSON null is stored as the literal null, like any other value.
 func (j Json) Value() (driver.Value, error) {
+	if !j.exists {
+		return nil, nil
+	}
+
 	bytes, err := j.MarshalJSON()
 	return driver.Value(bytes), err
 }
 
+// Reader returns a reader of the compact JSON encoding of the value, as given by Stringify.
+// The value is only encoded on the first Read, so a Reader that isn't read costs nothing,
+// and if it can't be encoded, every Read returns the encoding error.
+// To stream a big value without holding its whole encoding in memory, use WriteTo.
 func (j Json) Reader() io.Reader {
-	//TODO: is ignoring the error here is ok?
-	bytes, _ := json.Marshal(j)
+	return &lazyReader{j: j}
+}
+
+// lazyReader encodes its Json when it's first read
+type lazyReader struct {
+	j   Json
+	r   *bytes.Reader
+	err error
+}
+
+func (l *lazyReader) Read(p []byte) (int, error) {
+	if l.r == nil && l.err == nil {
+		var b []byte
+		if b, l.err = json.Marshal(l.j); l.err == nil {
+			l.r = bytes.NewReader(b)
+		}
+	}
+	if l.err != nil {
+		return 0, l.err
+	}
 
-	return strings.NewReader(string(bytes))
+	return l.r.Read(p)
 }
 
 ////
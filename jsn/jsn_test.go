@@ -1,7 +1,15 @@
 package jsn
 
 import (
+	"bytes"
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -49,7 +57,7 @@ func TestUnmarshalToJson(t *testing.T) {
 	assert.Equal(t, j.K("deep").K("no").NullOrUndefined(), true)
 
 	assert.NotNil(t, j.K("koko").Raw())
-	assert.Equal(t, 1.0, j.K("koko").Raw().(float64))
+	assert.Equal(t, json.Number("1"), j.K("koko").Raw().(json.Number))
 	assert.Nil(t, j.K("no").Raw())
 
 	require.Len(t, j.K("arr").Array().Elements(), 3)
@@ -71,6 +79,55 @@ func TestUnmarshalToJson(t *testing.T) {
 	require.Len(t, j.K("sarr").Array().Elements(), 2)
 }
 
+func TestGetE(t *testing.T) {
+	j, err := NewJson(`{"a": {"b": null}, "list": ["x", "y"], "s": "str"}`)
+	require.NoError(t, err)
+
+	v, err := j.GetE("a")
+	require.NoError(t, err)
+	assert.True(t, j.K("a").Equal(v))
+
+	v, err = v.GetE("b")
+	require.NoError(t, err)
+	assert.True(t, v.Null())
+
+	v, err = j.GetE("foo")
+	assert.EqualError(t, err, `key "foo" not found`)
+	assert.True(t, v.Undefined())
+
+	for _, notObject := range []Json{j.K("s"), j.K("list"), j.K("a").K("b"), j.K("missing")} {
+		v, err = notObject.GetE("b")
+		assert.EqualError(t, err, "value is not an object")
+		assert.True(t, v.Undefined())
+	}
+}
+
+func TestIE(t *testing.T) {
+	j, err := NewJson(`{"list": ["x", null], "s": "str"}`)
+	require.NoError(t, err)
+	list := j.K("list")
+
+	v, err := list.IE(0)
+	require.NoError(t, err)
+	assert.Equal(t, "x", v.String().Value)
+
+	v, err = list.IE(1)
+	require.NoError(t, err)
+	assert.True(t, v.Null())
+
+	v, err = list.IE(3)
+	assert.EqualError(t, err, "index 3 out of range (len 2)")
+	assert.True(t, v.Undefined())
+	_, err = list.IE(-1)
+	assert.EqualError(t, err, "index -1 out of range (len 2)")
+
+	for _, notArray := range []Json{j, j.K("s"), j.K("missing")} {
+		v, err = notArray.IE(0)
+		assert.EqualError(t, err, "value is not an array")
+		assert.True(t, v.Undefined())
+	}
+}
+
 func TestItemMap(t *testing.T) {
 	j, err := NewJson(`{
 		"a": 1, 	
@@ -120,6 +177,105 @@ func TestItemMap(t *testing.T) {
 	assert.Equal(t, 0, count)
 }
 
+func TestIterMapSorted(t *testing.T) {
+	j, err := NewJson(`{"c": 3, "a": 1, "d": 4, "b": 2}`)
+	require.NoError(t, err)
+
+	var keys []string
+	var values []int
+	count := j.IterMapSorted(func(k string, v Json) bool {
+		keys = append(keys, k)
+		values = append(values, v.Int().Value)
+		return true
+	})
+	assert.Equal(t, 4, count)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, keys)
+	assert.Equal(t, []int{1, 2, 3, 4}, values)
+
+	keys = nil
+	count = j.IterMapSorted(func(k string, v Json) bool {
+		keys = append(keys, k)
+		return k != "b"
+	})
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{"a", "b"}, keys)
+
+	count = j.K("a").IterMapSorted(func(k string, v Json) bool {
+		assert.True(t, false, "should not be executed")
+		return true
+	})
+	assert.Equal(t, 0, count)
+}
+
+func TestIterArray(t *testing.T) {
+	j, err := NewJson(`["a", "b", "c"]`)
+	require.NoError(t, err)
+
+	var visited []string
+	count := j.IterArray(func(i int, v Json) bool {
+		assert.Equal(t, j.I(i), v)
+		visited = append(visited, v.String().Value)
+		return true
+	})
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []string{"a", "b", "c"}, visited)
+
+	count = j.IterArray(func(i int, v Json) bool {
+		return i < 1
+	})
+	assert.Equal(t, 2, count)
+
+	m, err := NewJson(`{"a": [1]}`)
+	require.NoError(t, err)
+	count = m.IterArray(func(i int, v Json) bool {
+		assert.True(t, false, "should not be executed")
+		return true
+	})
+	assert.Equal(t, 0, count)
+	assert.Equal(t, 0, m.K("no").IterArray(func(i int, v Json) bool { return true }))
+}
+
+func TestForEachChild(t *testing.T) {
+	j, err := NewJson(`{"obj": {"b": 2, "a": 1, "c": 3}, "arr": ["x", "y", "z"], "s": "str"}`)
+	require.NoError(t, err)
+
+	var keys []string
+	count := j.K("obj").ForEachChild(func(key string, v Json) bool {
+		assert.Equal(t, j.K("obj").K(key), v)
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+
+	var indexes, values []string
+	count = j.K("arr").ForEachChild(func(index string, v Json) bool {
+		indexes = append(indexes, index)
+		values = append(values, v.String().Value)
+		return true
+	})
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []string{"0", "1", "2"}, indexes)
+	assert.Equal(t, []string{"x", "y", "z"}, values)
+
+	count = j.K("arr").ForEachChild(func(index string, v Json) bool {
+		return index != "1"
+	})
+	assert.Equal(t, 2, count)
+	count = j.K("obj").ForEachChild(func(key string, v Json) bool {
+		return false
+	})
+	assert.Equal(t, 1, count)
+
+	for _, leaf := range []Json{j.K("s"), j.K("no")} {
+		count = leaf.ForEachChild(func(string, Json) bool {
+			assert.True(t, false, "should not be executed")
+			return true
+		})
+		assert.Equal(t, 0, count)
+	}
+}
+
 func TestBadArrays(t *testing.T) {
 	j, err := NewJson(`{
 		"a": null,
@@ -139,6 +295,22 @@ func TestBadArrays(t *testing.T) {
 	assert.True(t, j.K("a").I(0).Undefined())
 }
 
+func TestFirstArray(t *testing.T) {
+	j, err := NewJson(`{"items": null, "entries": "none", "results": [1, 2], "data": [3]}`)
+	require.NoError(t, err)
+
+	a := j.FirstArray("items", "entries", "missing", "results", "data")
+	assert.True(t, a.IsValid)
+	assert.Equal(t, []Json{j.K("results").I(0), j.K("results").I(1)}, a.Elements())
+
+	a = j.FirstArray("items", "entries")
+	assert.False(t, a.IsValid)
+	assert.Len(t, a.Elements(), 0)
+
+	assert.False(t, j.K("results").FirstArray("results").IsValid)
+	assert.False(t, j.FirstArray().IsValid)
+}
+
 func TestMarshalMap(t *testing.T) {
 	j := Map{
 		"key": "value",
@@ -192,6 +364,96 @@ func TestMarshalJson(t *testing.T) {
 ]`, j.Pretty())
 }
 
+func TestStringifyRaw(t *testing.T) {
+	j, err := NewJson(`{"html": "<b>Tom & Jerry</b>", "n": [1]}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"html":"\u003cb\u003eTom \u0026 Jerry\u003c/b\u003e","n":[1]}`, j.Stringify(), "Stringify still escapes")
+	assert.Equal(t, `{"html":"<b>Tom & Jerry</b>","n":[1]}`, j.StringifyRaw())
+	assert.Equal(t, `{
+>  "html": "<b>Tom & Jerry</b>",
+>  "n": [
+>    1
+>  ]
+>}`, j.StringifyRawIndent(">", "  "))
+
+	assert.Equal(t, "null", Json{}.StringifyRaw())
+	assert.Equal(t, "", Json{data: make(chan int), exists: true}.StringifyRaw())
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteTo(t *testing.T) {
+	j, err := NewJson(`{"b": [1, 2], "a": "<x>"}`)
+	require.NoError(t, err)
+
+	var _ io.WriterTo = j
+
+	var buf bytes.Buffer
+	n, err := j.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, j.Stringify()+"\n", buf.String())
+	assert.Equal(t, int64(buf.Len()), n)
+
+	buf.Reset()
+	n, err = j.WriteIndentedTo(&buf, "", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, j.Pretty()+"\n", buf.String())
+	assert.Equal(t, int64(buf.Len()), n)
+
+	buf.Reset()
+	_, err = Json{}.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "null\n", buf.String())
+
+	n, err = j.WriteTo(failingWriter{})
+	assert.EqualError(t, err, "disk full")
+	assert.Equal(t, int64(0), n)
+
+	_, err = Json{data: make(chan int), exists: true}.WriteTo(&buf)
+	assert.Error(t, err)
+}
+
+func TestReader(t *testing.T) {
+	m := Map{}
+	for i := 0; i < 10000; i++ {
+		m[strconv.Itoa(i)] = Map{"index": i, "tags": []string{"a", "<b>"}}
+	}
+	j := m.Json()
+
+	b, err := ioutil.ReadAll(j.Reader())
+	require.NoError(t, err)
+	assert.Equal(t, j.Stringify(), string(b))
+
+	decoded, err := NewJson(j.Reader())
+	require.NoError(t, err)
+	assert.True(t, j.Equal(decoded))
+
+	b, err = ioutil.ReadAll(Json{}.Reader())
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(b))
+}
+
+func TestReaderError(t *testing.T) {
+	r := Json{data: map[string]interface{}{"c": make(chan int)}, exists: true}.Reader()
+
+	buf := make([]byte, 16)
+	n, err := r.Read(buf)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported type")
+	assert.Equal(t, 0, n)
+
+	_, err = r.Read(buf)
+	assert.Error(t, err, "the error is returned by every Read")
+
+	_, err = ioutil.ReadAll(r)
+	assert.Error(t, err)
+}
+
 func TestNew(t *testing.T) {
 	js, err := NewJson([]byte(`{"koko":"moko"}`))
 	assert.NoError(t, err)
@@ -207,11 +469,20 @@ func TestNew(t *testing.T) {
 
 	js, err = NewJson("123")
 	assert.NoError(t, err)
-	assert.Equal(t, Json{float64(123), true}, js)
+	assert.Equal(t, Json{data: json.Number("123"), exists: true}, js)
 
 	js, err = NewJson(123)
 	require.NoError(t, err)
-	assert.Equal(t, Json{float64(123), true}, js)
+	assert.Equal(t, Json{data: json.Number("123"), exists: true}, js)
+
+	js, err = NewJson(`{"id": 12345678901234567}`)
+	require.NoError(t, err)
+	assert.Equal(t, json.Number("12345678901234567"), js.K("id").Raw())
+	assert.Equal(t, Int64{12345678901234567, true}, js.K("id").Int64())
+
+	js, err = NewJson(strings.NewReader(`[9007199254740993]`))
+	require.NoError(t, err)
+	assert.Equal(t, Int64{9007199254740993, true}, js.I(0).Int64())
 }
 
 func TestNewFromMap(t *testing.T) {
@@ -241,6 +512,39 @@ func TestNewFromReader(t *testing.T) {
 	j, err := NewJson(reader)
 	assert.NoError(t, err)
 	assert.Equal(t, "moko", j.K("koko").String().Value)
+
+	for _, src := range []string{`{"a":1} junk`, `{"a":1} {"b":2}`, `1 2`} {
+		_, err = NewJson(strings.NewReader(src))
+		assert.EqualError(t, err, "invalid data after top-level value", src)
+		_, err = NewJson(src)
+		assert.EqualError(t, err, "invalid data after top-level value", src)
+	}
+}
+
+func TestNewWithBOM(t *testing.T) {
+	doc := "\xEF\xBB\xBF \r\n\t{\"koko\": \"moko\"}\n "
+
+	j, err := NewJson(doc)
+	require.NoError(t, err)
+	assert.Equal(t, "moko", j.K("koko").String().Value)
+
+	j, err = NewJson([]byte(doc))
+	require.NoError(t, err)
+	assert.Equal(t, "moko", j.K("koko").String().Value)
+
+	j, err = NewJson(strings.NewReader(doc))
+	require.NoError(t, err)
+	assert.Equal(t, "moko", j.K("koko").String().Value)
+
+	j, err = NewJson(strings.NewReader("\xEF\xBB\xBF1"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, j.Int().Value)
+
+	_, err = NewJson("\xEF\xBB\xBF\xEF\xBB\xBF{}")
+	assert.Error(t, err)
+
+	_, err = NewJson("\x00{}")
+	assert.Error(t, err)
 }
 
 func TestUnmarshal(t *testing.T) {
@@ -297,3 +601,255 @@ func TestUnmarshalArray(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, jarr.Array().IsValid)
 }
+
+func TestIsZero(t *testing.T) {
+	j, err := NewJson(`{"a": null, "b": 0, "c": "", "d": {}}`)
+	require.NoError(t, err)
+
+	assert.True(t, j.K("a").IsZero())
+	assert.True(t, j.K("no").IsZero())
+	assert.False(t, j.K("b").IsZero())
+	assert.False(t, j.K("c").IsZero())
+	assert.False(t, j.K("d").IsZero())
+}
+
+func TestUnmarshalKeepsNumbers(t *testing.T) {
+	type Event struct {
+		Name string `json:"name"`
+		Data Json   `json:"data"`
+	}
+
+	var e Event
+	err := json.Unmarshal([]byte(`{"name": "e", "data": {"id": 12345678901234567, "ratio": 0.25, "neg": -2.5}}`), &e)
+	require.NoError(t, err)
+
+	assert.Equal(t, json.Number("12345678901234567"), e.Data.K("id").Raw())
+	assert.Equal(t, Int64{12345678901234567, true}, e.Data.K("id").Int64())
+	assert.Equal(t, Float64{0.25, true}, e.Data.K("ratio").Float64())
+	assert.Equal(t, Int{-2, true}, e.Data.K("neg").Int())
+	assert.Equal(t, `{"id":12345678901234567,"neg":-2.5,"ratio":0.25}`, e.Data.Stringify())
+
+	var j Json
+	assert.Error(t, j.UnmarshalJSON([]byte(`{"a": 1} {"b": 2}`)))
+	assert.True(t, j.Undefined())
+	assert.Error(t, j.UnmarshalJSON([]byte(`{"a": `)))
+	assert.NoError(t, j.UnmarshalJSON([]byte(` [1] `)))
+	assert.Equal(t, 1, j.I(0).Int().Value)
+
+	assert.Equal(t, Int64{}, Json{data: json.Number("1e30"), exists: true}.Int64())
+}
+
+func TestHasAndIsEmpty(t *testing.T) {
+	j, err := NewJson(`{"name": "x", "zero": 0, "off": false, "null": null, "blank": "", "list": [], "obj": {}, "items": [1]}`)
+	require.NoError(t, err)
+
+	assert.True(t, j.Has("name"))
+	assert.True(t, j.Has("zero"))
+	assert.True(t, j.Has("off"))
+	assert.True(t, j.Has("items"))
+	assert.False(t, j.Has("null"))
+	assert.False(t, j.Has("blank"))
+	assert.False(t, j.Has("list"))
+	assert.False(t, j.Has("obj"))
+	assert.False(t, j.Has("absent"))
+	assert.False(t, j.K("name").Has("name"))
+
+	assert.True(t, j.K("absent").IsEmpty())
+	assert.True(t, j.K("null").IsEmpty())
+	assert.True(t, j.K("obj").IsEmpty())
+	assert.False(t, j.K("zero").IsEmpty())
+	assert.False(t, j.IsEmpty())
+}
+
+func TestLen(t *testing.T) {
+	j, err := NewJson(`{"list": [1, 2, 3], "obj": {"a": 1, "b": 2}, "s": "héllo", "empty": "", "n": 42, "b": true, "null": null}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, 7, j.Len())
+	assert.Equal(t, 3, j.K("list").Len())
+	assert.Equal(t, 2, j.K("obj").Len())
+	assert.Equal(t, 5, j.K("s").Len())
+	assert.Equal(t, 0, j.K("empty").Len())
+	assert.Equal(t, 0, j.K("n").Len())
+	assert.Equal(t, 0, j.K("b").Len())
+	assert.Equal(t, 0, j.K("null").Len())
+	assert.Equal(t, 0, j.K("missing").Len())
+}
+
+func TestUint64(t *testing.T) {
+	j := decodeUseNumber(t, `{"max": 18446744073709551615, "big": 9223372036854775808, "small": 42, "frac": 2.9, "exp": 1e3, "neg": -1, "over": 18446744073709551616, "s": "1"}`)
+
+	assert.Equal(t, Uint64{math.MaxUint64, true}, j.K("max").Uint64())
+	assert.Equal(t, Uint64{1 << 63, true}, j.K("big").Uint64())
+	assert.Equal(t, Uint64{42, true}, j.K("small").Uint64())
+	assert.Equal(t, Uint64{2, true}, j.K("frac").Uint64())
+	assert.Equal(t, Uint64{1000, true}, j.K("exp").Uint64())
+	assert.Equal(t, Uint64{}, j.K("neg").Uint64())
+	assert.Equal(t, Uint64{}, j.K("over").Uint64())
+	assert.Equal(t, Uint64{}, j.K("s").Uint64())
+	assert.Equal(t, Uint64{}, j.K("missing").Uint64())
+	assert.Equal(t, Uint{42, true}, j.K("small").Uint())
+	assert.Equal(t, Uint{}, j.K("neg").Uint())
+
+	f, err := NewJson(`{"n": 123.7, "neg": -0.5}`)
+	require.NoError(t, err)
+	assert.Equal(t, Uint64{123, true}, f.K("n").Uint64())
+	assert.Equal(t, Uint64{}, f.K("neg").Uint64())
+}
+
+func TestOrAccessors(t *testing.T) {
+	j, err := NewJson(`{"s": "", "i": 0, "big": 9007199254740993, "f": 2.5, "b": false, "n": null}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "", j.K("s").StringOr("default"))
+	assert.Equal(t, 0, j.K("i").IntOr(7))
+	assert.Equal(t, int64(9007199254740993), j.K("big").Int64Or(7))
+	assert.Equal(t, 2.5, j.K("f").Float64Or(7))
+	assert.Equal(t, 2, j.K("f").IntOr(7))
+	assert.Equal(t, false, j.K("b").BoolOr(true))
+
+	for _, key := range []string{"n", "missing"} {
+		v := j.K(key)
+		assert.Equal(t, "default", v.StringOr("default"), key)
+		assert.Equal(t, 7, v.IntOr(7), key)
+		assert.Equal(t, int64(7), v.Int64Or(7), key)
+		assert.Equal(t, 7.5, v.Float64Or(7.5), key)
+		assert.Equal(t, true, v.BoolOr(true), key)
+	}
+
+	// the wrong type falls back too
+	assert.Equal(t, "default", j.K("i").StringOr("default"))
+	assert.Equal(t, 7, j.K("s").IntOr(7))
+	assert.Equal(t, true, j.K("s").BoolOr(true))
+}
+
+func TestAsString(t *testing.T) {
+	j, err := NewJson(`{"s": "text", "i": 42, "f": 2.50, "big": 12345678901234567890, "b": true, "n": null, "a": [1, "x"], "o": {"k": {}}}`)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"s":       "text",
+		"i":       "42",
+		"f":       "2.50",
+		"big":     "12345678901234567890",
+		"b":       "true",
+		"n":       "null",
+		"a":       `[1,"x"]`,
+		"o":       `{"k":{}}`,
+		"missing": "",
+	}
+	for key, expected := range tests {
+		assert.Equal(t, expected, j.K(key).AsString(), key)
+	}
+
+	var floats interface{}
+	require.NoError(t, json.Unmarshal([]byte(`[2.5, 1e21, 0.0000001]`), &floats))
+	a := Json{data: floats, exists: true}
+	assert.Equal(t, "2.5", a.I(0).AsString())
+	assert.Equal(t, "1000000000000000000000", a.I(1).AsString())
+	assert.Equal(t, "0.0000001", a.I(2).AsString())
+}
+
+type cents int64
+
+func (c *cents) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	var dollars, rest int64
+	if _, err := fmt.Sscanf(s, "$%d.%d", &dollars, &rest); err != nil {
+		return err
+	}
+	*c = cents(dollars*100 + rest)
+	return nil
+}
+
+func TestDecodeWith(t *testing.T) {
+	j, err := NewJson(`{"order": {"id": 12345678901234567890, "price": "$12.34"}}`)
+	require.NoError(t, err)
+
+	var order struct {
+		ID    json.Number `json:"id"`
+		Price cents       `json:"price"`
+	}
+	err = j.K("order").DecodeWith(func(raw json.RawMessage) error {
+		assert.Equal(t, `{"id":12345678901234567890,"price":"$12.34"}`, string(raw))
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		return dec.Decode(&order)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, json.Number("12345678901234567890"), order.ID)
+	assert.Equal(t, cents(1234), order.Price)
+
+	var price cents
+	err = j.K("order").K("id").DecodeWith(func(raw json.RawMessage) error {
+		return price.UnmarshalJSON(raw)
+	})
+	assert.Error(t, err, "errors of fn are returned")
+
+	err = j.K("missing").DecodeWith(func(raw json.RawMessage) error {
+		assert.Equal(t, "null", string(raw))
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestScan(t *testing.T) {
+	var j Json
+	require.NoError(t, j.Scan([]byte(`{"a": 1}`)))
+	assert.Equal(t, `{"a":1}`, j.Stringify())
+
+	require.NoError(t, j.Scan(`{"b": [true]}`))
+	assert.Equal(t, `{"b":[true]}`, j.Stringify())
+
+	require.NoError(t, j.Scan(strings.NewReader(`"text"`)))
+	assert.Equal(t, "text", j.String().Value)
+
+	require.NoError(t, j.Scan("null"))
+	assert.True(t, j.Null())
+	assert.False(t, j.Undefined())
+
+	require.NoError(t, j.Scan(nil))
+	assert.True(t, j.Undefined())
+
+	assert.Error(t, j.Scan(`{"a": `))
+	assert.True(t, j.Undefined())
+	assert.EqualError(t, j.Scan(42), "unsupported src type int")
+}
+
+func TestValue(t *testing.T) {
+	var _ driver.Valuer = Json{}
+
+	j, err := NewJson(`{"a": null, "b": [1]}`)
+	require.NoError(t, err)
+
+	v, err := j.Value()
+	require.NoError(t, err)
+	assert.True(t, driver.IsValue(v))
+	assert.Equal(t, []byte(`{"a":null,"b":[1]}`), v)
+
+	v, err = j.K("a").Value()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("null"), v, "a JSON null is stored as the null literal")
+
+	v, err = j.K("missing").Value()
+	require.NoError(t, err)
+	assert.Nil(t, v, "an undefined Json is stored as SQL NULL")
+	assert.True(t, driver.IsValue(v))
+
+	// round trip through Scan
+	for _, original := range []Json{j, j.K("a"), j.K("missing")} {
+		v, err := original.Value()
+		require.NoError(t, err)
+
+		var scanned Json
+		require.NoError(t, scanned.Scan(v))
+		assert.True(t, original.Equal(scanned))
+		assert.Equal(t, original.Undefined(), scanned.Undefined())
+	}
+
+	_, err = Json{data: make(chan int), exists: true}.Value()
+	assert.Error(t, err)
+}
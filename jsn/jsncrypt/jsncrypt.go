@@ -0,0 +1,119 @@
+// Package jsncrypt encrypts selected fields of a jsn.Json document with AES-GCM,
+// so a document can be stored with its sensitive fields encrypted while the rest stays queryable.
+//
+// An encrypted field holds a string envelope:
+//
+//	jsncrypt:v1:<base64 of nonce || ciphertext>
+//
+// where base64 is the standard encoding with padding, the nonce is the 12 random bytes
+// GCM uses by default, and the ciphertext (which ends with the GCM tag) seals the JSON
+// encoding of the original value, so numbers, booleans and null decrypt to their own type.
+// The JSON Pointer of the field is authenticated as GCM additional data, so an envelope only
+// decrypts at the pointer it was encrypted for: one copied or swapped into another field fails.
+// Only scalar leaves (strings, numbers, booleans and null) can be encrypted.
+package jsncrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/michael-go/go-jsn/jsn"
+)
+
+// Prefix marks an encrypted field's value
+const Prefix = "jsncrypt:v1:"
+
+// EncryptFields returns a copy of j where the values at the given JSON Pointers are replaced
+// by their encrypted envelope. key is an AES key of 16, 24 or 32 bytes.
+// Returns an error if a pointer doesn't refer to a value, or refers to an object or an array.
+// j is never modified.
+func EncryptFields(j jsn.Json, key []byte, pointers ...string) (jsn.Json, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return jsn.Json{}, err
+	}
+
+	encrypted := j.Clone()
+	for _, ptr := range pointers {
+		v := encrypted.Pointer(ptr)
+		switch v.Type() {
+		case jsn.KindInvalid:
+			return jsn.Json{}, fmt.Errorf("%q: no such value", ptr)
+		case jsn.KindObject, jsn.KindArray:
+			return jsn.Json{}, fmt.Errorf("%q: can't encrypt %s, only scalar values", ptr, v.Type())
+		}
+
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return jsn.Json{}, err
+		}
+		sealed := aead.Seal(nonce, nonce, []byte(v.Stringify()), []byte(ptr))
+
+		encrypted, err = encrypted.TestAndSet(ptr, v, Prefix+base64.StdEncoding.EncodeToString(sealed))
+		if err != nil {
+			return jsn.Json{}, err
+		}
+	}
+
+	return encrypted, nil
+}
+
+// DecryptFields is the inverse of EncryptFields: it returns a copy of j where the envelopes
+// at the given JSON Pointers are replaced by the values they hold.
+// Returns an error if a pointer doesn't refer to an envelope, or if it fails to decrypt
+// (e.g. with the wrong key, if it was tampered with, or moved from another pointer).
+// j is never modified.
+func DecryptFields(j jsn.Json, key []byte, pointers ...string) (jsn.Json, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return jsn.Json{}, err
+	}
+
+	decrypted := j.Clone()
+	for _, ptr := range pointers {
+		v := decrypted.Pointer(ptr)
+		envelope := v.String()
+		if !envelope.IsValid || !strings.HasPrefix(envelope.Value, Prefix) {
+			return jsn.Json{}, fmt.Errorf("%q: not an encrypted value", ptr)
+		}
+
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(envelope.Value, Prefix))
+		if err != nil {
+			return jsn.Json{}, fmt.Errorf("%q: bad envelope: %v", ptr, err)
+		}
+		if len(sealed) < aead.NonceSize() {
+			return jsn.Json{}, fmt.Errorf("%q: bad envelope: too short", ptr)
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+		plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(ptr))
+		if err != nil {
+			return jsn.Json{}, fmt.Errorf("%q: %v", ptr, err)
+		}
+		value, err := jsn.NewJson(plaintext)
+		if err != nil {
+			return jsn.Json{}, fmt.Errorf("%q: bad plaintext: %v", ptr, err)
+		}
+
+		decrypted, err = decrypted.TestAndSet(ptr, v, value)
+		if err != nil {
+			return jsn.Json{}, err
+		}
+	}
+
+	return decrypted, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,88 @@
+package jsncrypt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michael-go/go-jsn/jsn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var key = []byte("0123456789abcdef0123456789abcdef")
+
+func TestRoundTrip(t *testing.T) {
+	j, err := jsn.NewJson(`{"user": {"name": "gopher", "ssn": "123-45-6789", "pin": 1234, "admin": true}, "cards": ["4111"]}`)
+	require.NoError(t, err)
+
+	encrypted, err := EncryptFields(j, key, "/user/ssn", "/user/pin", "/cards/0")
+	require.NoError(t, err)
+
+	for _, ptr := range []string{"/user/ssn", "/user/pin", "/cards/0"} {
+		envelope := encrypted.Pointer(ptr).String()
+		assert.True(t, envelope.IsValid, ptr)
+		assert.True(t, strings.HasPrefix(envelope.Value, Prefix), envelope.Value)
+	}
+	assert.NotContains(t, encrypted.Stringify(), "123-45-6789")
+	assert.Equal(t, "gopher", encrypted.Pointer("/user/name").String().Value)
+	assert.Equal(t, "123-45-6789", j.Pointer("/user/ssn").String().Value, "the original is left unchanged")
+
+	decrypted, err := DecryptFields(encrypted, key, "/user/ssn", "/user/pin", "/cards/0")
+	require.NoError(t, err)
+	assert.True(t, j.Equal(decrypted), decrypted.Pretty())
+	assert.Equal(t, 1234, decrypted.Pointer("/user/pin").Int().Value)
+}
+
+func TestEncryptFieldsErrors(t *testing.T) {
+	j, err := jsn.NewJson(`{"user": {"name": "gopher"}, "tags": ["a"]}`)
+	require.NoError(t, err)
+
+	_, err = EncryptFields(j, key, "/user")
+	assert.EqualError(t, err, `"/user": can't encrypt object, only scalar values`)
+	_, err = EncryptFields(j, key, "/tags")
+	assert.EqualError(t, err, `"/tags": can't encrypt array, only scalar values`)
+	_, err = EncryptFields(j, key, "/missing")
+	assert.EqualError(t, err, `"/missing": no such value`)
+	_, err = EncryptFields(j, []byte("short"), "/user/name")
+	assert.Error(t, err)
+}
+
+func TestDecryptFieldsErrors(t *testing.T) {
+	j, err := jsn.NewJson(`{"name": "gopher", "bad": "jsncrypt:v1:!!", "short": "jsncrypt:v1:AAAA"}`)
+	require.NoError(t, err)
+
+	_, err = DecryptFields(j, key, "/name")
+	assert.EqualError(t, err, `"/name": not an encrypted value`)
+	_, err = DecryptFields(j, key, "/bad")
+	assert.Error(t, err)
+	_, err = DecryptFields(j, key, "/short")
+	assert.EqualError(t, err, `"/short": bad envelope: too short`)
+
+	encrypted, err := EncryptFields(j, key, "/name")
+	require.NoError(t, err)
+	_, err = DecryptFields(encrypted, []byte("fedcba9876543210fedcba9876543210"), "/name")
+	assert.Error(t, err, "the wrong key fails authentication")
+}
+
+func TestDecryptFieldsMovedEnvelope(t *testing.T) {
+	j, err := jsn.NewJson(`{"ssn": "123-45-6789", "card": "4111"}`)
+	require.NoError(t, err)
+
+	encrypted, err := EncryptFields(j, key, "/ssn", "/card")
+	require.NoError(t, err)
+
+	moved, err := encrypted.TestAndSet("/card", encrypted.Pointer("/card"), encrypted.Pointer("/ssn"))
+	require.NoError(t, err)
+	_, err = DecryptFields(moved, key, "/card")
+	assert.Error(t, err, "an envelope copied to another pointer fails authentication")
+
+	swapped, err := moved.TestAndSet("/ssn", encrypted.Pointer("/ssn"), encrypted.Pointer("/card"))
+	require.NoError(t, err)
+	_, err = DecryptFields(swapped, key, "/ssn")
+	assert.Error(t, err)
+	_, err = DecryptFields(swapped, key, "/card")
+	assert.Error(t, err)
+
+	_, err = DecryptFields(encrypted, key, "/ssn", "/card")
+	assert.NoError(t, err)
+}
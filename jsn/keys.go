@@ -0,0 +1,104 @@
+package jsn
+
+import (
+	"sort"
+	"strings"
+)
+
+// Keys returns the sorted keys of this object.
+// returns an empty slice if this isn't an object
+func (j Json) Keys() []string {
+	m, ok := j.asMap()
+	if !ok {
+		return []string{}
+	}
+
+	return sortedKeys(m)
+}
+
+// Values returns the values of this object, in the order of their sorted keys (see Keys).
+// returns an empty slice if this isn't an object
+func (j Json) Values() []Json {
+	m, _ := j.asMap()
+
+	values := make([]Json, 0, len(m))
+	for _, k := range sortedKeys(m) {
+		values = append(values, j.keyChild(k, m[k], true))
+	}
+
+	return values
+}
+
+// KeysWithPrefix returns the sorted keys of this object that start with prefix.
+// returns an empty slice if there are none, or if this isn't an object
+func (j Json) KeysWithPrefix(prefix string) []string {
+	keys := []string{}
+
+	m, _ := j.asMap()
+	for k := range m {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// StripKeyPrefix returns a copy of this object where prefix is removed from the top-level
+// keys that start with it, e.g. to normalize namespaced "x-meta-*" fields.
+// When a stripped key collides with a key that was already there ("x-meta-a" and "a"),
+// the value of the prefixed key wins.
+// If this isn't an object, a copy of it is returned as is.
+func (j Json) StripKeyPrefix(prefix string) Json {
+	m, ok := j.asMap()
+	if !ok {
+		return Json{data: deepCopy(j.data), exists: j.exists}
+	}
+
+	stripped := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if !strings.HasPrefix(k, prefix) {
+			if _, taken := stripped[k]; !taken {
+				stripped[k] = deepCopy(v)
+			}
+			continue
+		}
+		stripped[strings.TrimPrefix(k, prefix)] = deepCopy(v)
+	}
+
+	return Json{data: stripped, exists: true}
+}
+
+// IntersectKeys returns a copy of this object with only the keys that also exist in other,
+// with this object's values. Only the presence of the keys matters, not their values.
+// If other isn't an object, the result is an empty object.
+// returns an undefined Json if this isn't an object
+func (j Json) IntersectKeys(other Json) Json {
+	return j.filterKeys(other, true)
+}
+
+// SubtractKeys returns a copy of this object without the keys that exist in other.
+// Only the presence of the keys matters, not their values.
+// If other isn't an object, the result has all the keys.
+// returns an undefined Json if this isn't an object
+func (j Json) SubtractKeys(other Json) Json {
+	return j.filterKeys(other, false)
+}
+
+func (j Json) filterKeys(other Json, inOther bool) Json {
+	m, ok := j.asMap()
+	if !ok {
+		return Json{}
+	}
+	o, _ := other.asMap()
+
+	filtered := map[string]interface{}{}
+	for k, v := range m {
+		if _, exists := o[k]; exists == inOther {
+			filtered[k] = deepCopy(v)
+		}
+	}
+
+	return Json{data: filtered, exists: true}
+}
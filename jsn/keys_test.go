@@ -0,0 +1,79 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysAndValues(t *testing.T) {
+	j, err := NewJson(`{"c": 3, "a": 1, "b": {"x": true}}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "b", "c"}, j.Keys())
+	assert.Equal(t, []Json{j.K("a"), j.K("b"), j.K("c")}, j.Values())
+
+	empty, err := NewJson(`{}`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, empty.Keys())
+	assert.Equal(t, []Json{}, empty.Values())
+
+	assert.Equal(t, []string{}, j.K("a").Keys())
+	assert.Equal(t, []Json{}, j.K("a").Values())
+	assert.Equal(t, []string{}, j.K("missing").Keys())
+	assert.Equal(t, []Json{}, j.K("missing").Values())
+}
+
+func TestKeysWithPrefix(t *testing.T) {
+	j, err := NewJson(`{"x-meta-b": 2, "x-meta-a": 1, "x-other": 3, "id": 4}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"x-meta-a", "x-meta-b"}, j.KeysWithPrefix("x-meta-"))
+	assert.Equal(t, []string{"id", "x-meta-a", "x-meta-b", "x-other"}, j.KeysWithPrefix(""))
+	assert.Equal(t, []string{}, j.KeysWithPrefix("nope"))
+	assert.Equal(t, []string{}, j.K("id").KeysWithPrefix(""))
+}
+
+func TestStripKeyPrefix(t *testing.T) {
+	j, err := NewJson(`{"x-meta-a": 1, "x-meta-b": {"c": 2}, "id": 4}`)
+	require.NoError(t, err)
+
+	stripped := j.StripKeyPrefix("x-meta-")
+	expected, err := NewJson(`{"a": 1, "b": {"c": 2}, "id": 4}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(stripped), stripped.Stringify())
+	assert.True(t, j.Exists("x-meta-a"), "receiver must not be modified")
+
+	colliding, err := NewJson(`{"x-a": "prefixed", "a": "plain", "b": "kept"}`)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		stripped = colliding.StripKeyPrefix("x-")
+		assert.Equal(t, `{"a":"prefixed","b":"kept"}`, stripped.Stringify())
+	}
+
+	assert.Equal(t, 4, j.K("id").StripKeyPrefix("x").Int().Value)
+	assert.True(t, j.K("no").StripKeyPrefix("x").Undefined())
+}
+
+func TestIntersectAndSubtractKeys(t *testing.T) {
+	a, err := NewJson(`{"host": "a", "port": 80, "tls": {"on": true}, "debug": true}`)
+	require.NoError(t, err)
+	b, err := NewJson(`{"host": "b", "port": 80, "timeout": 5}`)
+	require.NoError(t, err)
+	disjoint, err := NewJson(`{"x": 1}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"host":"a","port":80}`, a.IntersectKeys(b).Stringify())
+	assert.Equal(t, `{"debug":true,"tls":{"on":true}}`, a.SubtractKeys(b).Stringify())
+	assert.Equal(t, `{"timeout":5}`, b.SubtractKeys(a).Stringify())
+
+	assert.Equal(t, `{}`, a.IntersectKeys(disjoint).Stringify())
+	assert.True(t, a.Equal(a.SubtractKeys(disjoint)))
+
+	assert.Equal(t, `{}`, a.IntersectKeys(b.K("port")).Stringify())
+	assert.True(t, a.Equal(a.SubtractKeys(b.K("no"))))
+
+	assert.True(t, b.K("port").IntersectKeys(a).Undefined())
+	assert.True(t, b.K("port").SubtractKeys(a).Undefined())
+}
@@ -0,0 +1,138 @@
+package jsn
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Kind enumerates the types a JSON value can have
+type Kind int
+
+// The possible Kinds of a Json.
+// KindInvalid is the Kind of an undefined Json.
+const (
+	KindInvalid Kind = iota
+	KindNull
+	KindBool
+	KindNumber
+	KindString
+	KindArray
+	KindObject
+)
+
+var kindNames = map[Kind]string{
+	KindInvalid: "invalid",
+	KindNull:    "null",
+	KindBool:    "bool",
+	KindNumber:  "number",
+	KindString:  "string",
+	KindArray:   "array",
+	KindObject:  "object",
+}
+
+// String returns the lowercase name of the kind, e.g. "object"
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Type returns the Kind of the underlying value.
+// returns KindInvalid for an undefined Json and KindNull for a JSON null
+func (j Json) Type() Kind {
+	if !j.exists {
+		return KindInvalid
+	}
+
+	switch j.data.(type) {
+	case nil:
+		return KindNull
+	case bool:
+		return KindBool
+	case float64, json.Number:
+		return KindNumber
+	case string:
+		return KindString
+	case []interface{}:
+		return KindArray
+	case map[string]interface{}:
+		return KindObject
+	default:
+		return KindInvalid
+	}
+}
+
+// IsObject returns true if the value is a JSON object.
+// Like the other Is predicates, it inspects the value without building an accessor struct,
+// and returns false for null and undefined.
+func (j Json) IsObject() bool {
+	_, ok := j.data.(map[string]interface{})
+	return ok
+}
+
+// IsArray returns true if the value is a JSON array
+func (j Json) IsArray() bool {
+	_, ok := j.data.([]interface{})
+	return ok
+}
+
+// IsString returns true if the value is a JSON string
+func (j Json) IsString() bool {
+	_, ok := j.data.(string)
+	return ok
+}
+
+// IsNumber returns true if the value is a JSON number, as float64 or json.Number
+func (j Json) IsNumber() bool {
+	switch j.data.(type) {
+	case float64, json.Number:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsBool returns true if the value is a JSON boolean
+func (j Json) IsBool() bool {
+	_, ok := j.data.(bool)
+	return ok
+}
+
+// NewJsonTyped is like NewJson, but also verifies the structure of the result.
+// types maps JSON Pointers (RFC 6901) to the Kind required at that location.
+// An error naming the offending pointer is returned if a value is missing or of
+// a different Kind. Requiring KindInvalid asserts that the pointer is absent.
+// Locations not listed in types are unconstrained.
+func NewJsonTyped(src interface{}, types map[string]Kind) (Json, error) {
+	j, err := NewJson(src)
+	if err != nil {
+		return Json{}, err
+	}
+
+	pointers := make([]string, 0, len(types))
+	for p := range types {
+		pointers = append(pointers, p)
+	}
+	sort.Strings(pointers)
+
+	for _, p := range pointers {
+		v, err := j.resolvePointer(p)
+		if err != nil {
+			return Json{}, err
+		}
+
+		want, got := types[p], v.Type()
+		switch {
+		case got == want:
+			continue
+		case got == KindInvalid:
+			return Json{}, fmt.Errorf("%q: missing, expected %s", p, want)
+		default:
+			return Json{}, fmt.Errorf("%q: expected %s, got %s", p, want, got)
+		}
+	}
+
+	return j, nil
+}
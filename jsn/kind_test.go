@@ -0,0 +1,107 @@
+package jsn
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestType(t *testing.T) {
+	j, err := NewJson(`{"n": null, "b": false, "f": 1.5, "s": "", "a": [], "o": {}}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, KindObject, j.Type())
+	assert.Equal(t, KindNull, j.K("n").Type())
+	assert.Equal(t, KindBool, j.K("b").Type())
+	assert.Equal(t, KindNumber, j.K("f").Type())
+	assert.Equal(t, KindString, j.K("s").Type())
+	assert.Equal(t, KindArray, j.K("a").Type())
+	assert.Equal(t, KindObject, j.K("o").Type())
+	assert.Equal(t, KindInvalid, j.K("no").Type())
+}
+
+func TestKindString(t *testing.T) {
+	names := map[Kind]string{
+		KindInvalid: "invalid",
+		KindNull:    "null",
+		KindBool:    "bool",
+		KindNumber:  "number",
+		KindString:  "string",
+		KindArray:   "array",
+		KindObject:  "object",
+	}
+	for kind, name := range names {
+		assert.Equal(t, name, kind.String())
+	}
+	assert.Equal(t, "Kind(42)", Kind(42).String())
+	assert.Equal(t, "type is array", fmt.Sprintf("type is %s", KindArray))
+}
+
+func TestIsPredicates(t *testing.T) {
+	j, err := NewJson(`{"n": null, "b": false, "f": 1.5, "s": "", "a": [], "o": {}}`)
+	require.NoError(t, err)
+
+	type predicates struct{ object, array, str, number, boolean bool }
+	tests := map[string]predicates{
+		"o":  {object: true},
+		"a":  {array: true},
+		"s":  {str: true},
+		"f":  {number: true},
+		"b":  {boolean: true},
+		"n":  {},
+		"no": {},
+	}
+	for key, want := range tests {
+		v := j.K(key)
+		got := predicates{v.IsObject(), v.IsArray(), v.IsString(), v.IsNumber(), v.IsBool()}
+		assert.Equal(t, want, got, key)
+	}
+
+	var floats interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"f": 2}`), &floats))
+	assert.True(t, Json{data: floats, exists: true}.K("f").IsNumber())
+	assert.False(t, Json{}.IsObject())
+}
+
+func TestNewJsonTyped(t *testing.T) {
+	types := map[string]Kind{
+		"/id":         KindNumber,
+		"/name":       KindString,
+		"/tags":       KindArray,
+		"/tags/0":     KindString,
+		"/meta/a~1b":  KindBool,
+		"/deprecated": KindInvalid,
+	}
+
+	j, err := NewJsonTyped(`{
+		"id": 7,
+		"name": "gopher",
+		"tags": ["go"],
+		"meta": {"a/b": true},
+		"extra": {"anything": [1, 2]}
+	}`, types)
+	require.NoError(t, err)
+	assert.Equal(t, "gopher", j.K("name").String().Value)
+
+	j, err = NewJsonTyped(`{"id": "7", "name": "gopher", "tags": ["go"], "meta": {"a/b": true}}`, types)
+	assert.EqualError(t, err, `"/id": expected number, got string`)
+	assert.Equal(t, Json{}, j)
+
+	_, err = NewJsonTyped(`{"id": 7, "name": "gopher", "tags": [], "meta": {"a/b": true}}`, types)
+	assert.EqualError(t, err, `"/tags/0": missing, expected string`)
+
+	_, err = NewJsonTyped(`{"id": 7, "name": "gopher", "tags": ["go"], "meta": {"a/b": true}, "deprecated": 1}`, types)
+	assert.EqualError(t, err, `"/deprecated": expected invalid, got number`)
+
+	_, err = NewJsonTyped(`[1, 2]`, map[string]Kind{"": KindObject})
+	assert.EqualError(t, err, `"": expected object, got array`)
+
+	_, err = NewJsonTyped(`{}`, map[string]Kind{"id": KindNumber})
+	assert.Error(t, err)
+
+	_, err = NewJsonTyped(`{broken`, nil)
+	assert.Error(t, err)
+}
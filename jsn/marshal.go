@@ -0,0 +1,54 @@
+package jsn
+
+import "encoding/json"
+
+// MarshalOptions configures MarshalWith. The zero value marshals like Marshal.
+type MarshalOptions struct {
+	// EmptyAsNull writes null instead of {} for objects with no keys, and instead of []
+	// for arrays with no elements, at any depth including the top level, for consumers that
+	// expect null for empty values. Only containers that are empty in the document are
+	// affected: an object whose values are all empty arrays, e.g. {"a": []}, is written
+	// as {"a":null}, not as null. Off by default.
+	EmptyAsNull bool
+}
+
+// MarshalWith is like Marshal, with the output adjusted by opts.
+// The Json itself isn't modified.
+func (j Json) MarshalWith(opts MarshalOptions) (string, error) {
+	data := j.data
+	if opts.EmptyAsNull {
+		data = emptyAsNull(data)
+	}
+
+	buf, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// emptyAsNull returns a copy of data where empty objects and arrays are replaced by nil
+func emptyAsNull(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = emptyAsNull(e)
+		}
+		return m
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		a := make([]interface{}, len(v))
+		for i, e := range v {
+			a[i] = emptyAsNull(e)
+		}
+		return a
+	default:
+		return v
+	}
+}
@@ -0,0 +1,42 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalWith(t *testing.T) {
+	j, err := NewJson(`{"obj": {}, "arr": [], "nested": {"a": [], "b": [{}, 1]}, "s": "", "n": null, "zero": 0}`)
+	require.NoError(t, err)
+
+	out, err := j.MarshalWith(MarshalOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, j.Stringify(), out, "off by default")
+	assert.Equal(t, `{"arr":[],"n":null,"nested":{"a":[],"b":[{},1]},"obj":{},"s":"","zero":0}`, out)
+
+	out, err = j.MarshalWith(MarshalOptions{EmptyAsNull: true})
+	require.NoError(t, err)
+	assert.Equal(t, `{"arr":null,"n":null,"nested":{"a":null,"b":[null,1]},"obj":null,"s":"","zero":0}`, out)
+	assert.Equal(t, `{"arr":[],"n":null,"nested":{"a":[],"b":[{},1]},"obj":{},"s":"","zero":0}`, j.Stringify(), "original must not be modified")
+}
+
+func TestMarshalWithTopLevel(t *testing.T) {
+	for _, src := range []string{`{}`, `[]`} {
+		j, err := NewJson(src)
+		require.NoError(t, err)
+
+		out, err := j.MarshalWith(MarshalOptions{EmptyAsNull: true})
+		require.NoError(t, err)
+		assert.Equal(t, "null", out, src)
+
+		out, err = j.MarshalWith(MarshalOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, src, out)
+	}
+
+	out, err := Json{}.MarshalWith(MarshalOptions{EmptyAsNull: true})
+	require.NoError(t, err)
+	assert.Equal(t, "null", out)
+}
@@ -0,0 +1,227 @@
+package jsn
+
+import (
+	"fmt"
+	"strings"
+)
+
+type mergeConfig struct {
+	onConflict func(pointer string, a, b Json) Json
+}
+
+// MergeOption configures Merge
+type MergeOption func(*mergeConfig)
+
+// WithConflictHandler makes Merge call f to pick the value for every leaf conflict,
+// instead of letting the merged document's value win.
+// A leaf conflict is a key present in both documents with different values (per Equal)
+// where the values aren't both objects (objects are merged instead). Arrays are leaves.
+// f gets the JSON Pointer of the key and both values, and returns the value to keep,
+// which may be a, b, or anything else. Returning an undefined Json drops the key.
+func WithConflictHandler(f func(pointer string, a, b Json) Json) MergeOption {
+	return func(c *mergeConfig) {
+		c.onConflict = f
+	}
+}
+
+// Merge returns a new Json with the keys of other deep-merged into this object:
+// keys only in other are added, and keys in both are merged recursively when both
+// values are objects. For other conflicting values, other wins (see WithConflictHandler).
+// Arrays are not merged: an array in other replaces the array in this object wholesale.
+// If this Json isn't an object, it's returned unchanged; if other isn't an object,
+// other is returned (or this object, if other is undefined).
+// The result shares no state with either document.
+func (j Json) Merge(other Json, opts ...MergeOption) Json {
+	var c mergeConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if _, ok := j.asMap(); !ok {
+		return Json{data: deepCopy(j.data), exists: j.exists}
+	}
+	if _, ok := other.asMap(); !ok {
+		if !other.exists {
+			return Json{data: deepCopy(j.data), exists: true}
+		}
+		return Json{data: deepCopy(other.data), exists: true}
+	}
+
+	merged, _ := c.merge("", j.data, other.data)
+	return Json{data: merged, exists: true}
+}
+
+// MergeAll deep-merges docs from left to right, each one merged into the result of the
+// previous ones with the same rules and options as Merge, so later documents override
+// earlier ones: MergeAll([]Json{base, env, local, flags}) lets flags win over everything.
+// Undefined documents are skipped. Returns an empty object for no documents, and an error
+// if a document is defined but isn't an object.
+// The result shares no state with any of the documents.
+func MergeAll(docs []Json, opts ...MergeOption) (Json, error) {
+	var c mergeConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	var merged interface{} = map[string]interface{}{}
+	for i, doc := range docs {
+		if !doc.exists {
+			continue
+		}
+		if _, ok := doc.asMap(); !ok {
+			return Json{}, fmt.Errorf("document %d is not an object", i)
+		}
+
+		merged, _ = c.merge("", merged, doc.data)
+	}
+
+	return Json{data: merged, exists: true}, nil
+}
+
+// NamedJson is a document with a name, e.g. the config file it was read from
+type NamedJson struct {
+	Name string
+	Doc  Json
+}
+
+// MergeAllTracked is like MergeAll, but also reports which source supplied each final value.
+// Sources are a slice rather than a map, since their order sets the precedence.
+// The returned map goes from the JSON Pointer of every leaf of the merged document to the
+// name of the source it came from, i.e. the last source holding an equal value there.
+// Leaves are the values Merge doesn't merge: scalars, arrays (replaced wholesale)
+// and empty objects. A value produced by a conflict handler that no source holds has no entry.
+func MergeAllTracked(sources []NamedJson, opts ...MergeOption) (Json, map[string]string, error) {
+	docs := make([]Json, len(sources))
+	for i, s := range sources {
+		if _, ok := s.Doc.asMap(); s.Doc.exists && !ok {
+			return Json{}, nil, fmt.Errorf("source %q is not an object", s.Name)
+		}
+		docs[i] = s.Doc
+	}
+
+	merged, err := MergeAll(docs, opts...)
+	if err != nil {
+		return Json{}, nil, err
+	}
+
+	provenance := map[string]string{}
+	forEachMergeLeaf("", merged.data, func(pointer string, v Json) {
+		for i := len(sources) - 1; i >= 0; i-- {
+			if source, err := sources[i].Doc.resolvePointer(pointer); err == nil && source.Equal(v) {
+				provenance[pointer] = sources[i].Name
+				return
+			}
+		}
+	})
+
+	return merged, provenance, nil
+}
+
+// forEachMergeLeaf calls f for every value Merge treats as a leaf:
+// anything but non-empty objects, which are walked into
+func forEachMergeLeaf(pointer string, data interface{}, f func(pointer string, v Json)) {
+	m, ok := data.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		f(pointer, Json{data: data, exists: true})
+		return
+	}
+
+	for _, k := range sortedKeys(m) {
+		forEachMergeLeaf(pointer+"/"+escapePointerToken(k), m[k], f)
+	}
+}
+
+// merge deep-merges b into a copy of a: objects are merged key by key, and for anything
+// else b wins, unless there's a conflict handler.
+// returns false if the value should be dropped.
+// the result shares no state with a or b
+func (c *mergeConfig) merge(pointer string, a, b interface{}) (interface{}, bool) {
+	am, aIsMap := a.(map[string]interface{})
+	bm, bIsMap := b.(map[string]interface{})
+	if !aIsMap || !bIsMap {
+		if c.onConflict != nil && !equalValues(a, b) {
+			chosen := c.onConflict(pointer, Json{data: a, exists: true}, Json{data: b, exists: true})
+			return deepCopy(chosen.data), chosen.exists
+		}
+		return deepCopy(b), true
+	}
+
+	merged := deepCopy(am).(map[string]interface{})
+	for k, v := range bm {
+		existing, ok := merged[k]
+		if !ok {
+			merged[k] = deepCopy(v)
+			continue
+		}
+
+		if value, keep := c.merge(pointer+"/"+escapePointerToken(k), existing, v); keep {
+			merged[k] = value
+		} else {
+			delete(merged, k)
+		}
+	}
+
+	return merged, true
+}
+
+// Merge3Report renders the conflicts of a three-way merge of mine and theirs, two documents
+// derived from base, for display in review tools. A conflict is a location both sides changed
+// differently: objects changed on both sides are compared key by key, anything else (including
+// arrays) is compared as a whole. Changes made on one side only, or identically on both, merge
+// cleanly and aren't reported.
+// Each conflict is rendered like a git diff3 conflict marker, headed by its JSON Pointer,
+// with values as compact JSON (or "(missing)" when a side doesn't have the key):
+//
+//	<<<<<<< mine "/db/port"
+//	5433
+//	||||||| base
+//	5432
+//	=======
+//	6543
+//	>>>>>>> theirs
+//
+// Conflicts are sorted by pointer and separated by blank lines.
+// Returns an empty string if there are no conflicts.
+func Merge3Report(base, mine, theirs Json) string {
+	var conflicts []string
+	forEachMerge3Conflict("", base, mine, theirs, func(pointer string, b, m, t Json) {
+		conflicts = append(conflicts, fmt.Sprintf("<<<<<<< mine %q\n%s\n||||||| base\n%s\n=======\n%s\n>>>>>>> theirs\n",
+			pointer, reportValue(m), reportValue(b), reportValue(t)))
+	})
+
+	return strings.Join(conflicts, "\n")
+}
+
+// forEachMerge3Conflict calls f, in pointer order, for every location where mine and theirs
+// both changed base, to different values
+func forEachMerge3Conflict(pointer string, base, mine, theirs Json, f func(pointer string, b, m, t Json)) {
+	if mine.Equal(theirs) || mine.Equal(base) || theirs.Equal(base) {
+		return
+	}
+
+	mm, mineIsMap := mine.asMap()
+	tm, theirsIsMap := theirs.asMap()
+	if _, baseIsMap := base.asMap(); !mineIsMap || !theirsIsMap || (base.exists && !baseIsMap) {
+		f(pointer, base, mine, theirs)
+		return
+	}
+
+	keys := map[string]interface{}{}
+	for k := range mm {
+		keys[k] = nil
+	}
+	for k := range tm {
+		keys[k] = nil
+	}
+	for _, k := range sortedKeys(keys) {
+		forEachMerge3Conflict(pointer+"/"+escapePointerToken(k), base.Get(k), mine.Get(k), theirs.Get(k), f)
+	}
+}
+
+// reportValue formats a value for Merge3Report
+func reportValue(j Json) string {
+	if !j.exists {
+		return "(missing)"
+	}
+	return j.Stringify()
+}
@@ -0,0 +1,234 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	a, err := NewJson(`{"name": "a", "limits": {"cpu": 1, "mem": 512}, "tags": ["x"], "only_a": true}`)
+	require.NoError(t, err)
+	b, err := NewJson(`{"name": "b", "limits": {"mem": 1024, "disk": 10}, "tags": ["y", "z"], "only_b": null}`)
+	require.NoError(t, err)
+
+	merged := a.Merge(b)
+	expected, err := NewJson(`{
+		"name": "b",
+		"limits": {"cpu": 1, "mem": 1024, "disk": 10},
+		"tags": ["y", "z"],
+		"only_a": true,
+		"only_b": null
+	}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(merged), merged.Pretty())
+
+	// no shared state
+	merged.K("limits").Raw().(map[string]interface{})["cpu"] = 100
+	assert.Equal(t, 1, a.K("limits").K("cpu").Int().Value)
+}
+
+func TestMergeNonObjects(t *testing.T) {
+	j, err := NewJson(`{"obj": {"a": 1}, "list": [1, 2], "name": "x"}`)
+	require.NoError(t, err)
+	obj, list, name := j.K("obj"), j.K("list"), j.K("name")
+
+	// the non-object side is returned
+	assert.True(t, list.Equal(list.Merge(obj)))
+	assert.True(t, name.Equal(obj.Merge(name)))
+	assert.True(t, list.Equal(obj.Merge(list)), "arrays replace objects wholesale")
+
+	// undefined on either side
+	assert.True(t, obj.Equal(obj.Merge(Json{})))
+	assert.True(t, Json{}.Merge(obj).Undefined())
+
+	// results are copies
+	merged := obj.Merge(Json{})
+	require.NoError(t, merged.Set("b", 2))
+	assert.False(t, j.K("obj").Exists("b"))
+
+	nested, err := NewJson(`{"a": {"b": [1, 2], "c": 1}}`)
+	require.NoError(t, err)
+	patch, err := NewJson(`{"a": {"b": [3]}}`)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":{"b":[3],"c":1}}`, nested.Merge(patch).Stringify())
+}
+
+func TestMergeConflictHandler(t *testing.T) {
+	a, err := NewJson(`{"price": 10, "stock": {"count": 3, "min": 1}, "same": 5, "drop": 1, "kind": "x"}`)
+	require.NoError(t, err)
+	b, err := NewJson(`{"price": 7, "stock": {"count": 8}, "same": 5, "drop": 2, "kind": {"name": "x"}}`)
+	require.NoError(t, err)
+
+	var conflicts []string
+	smaller := WithConflictHandler(func(pointer string, x, y Json) Json {
+		conflicts = append(conflicts, pointer)
+		if pointer == "/drop" {
+			return Json{}
+		}
+		if x.Float64().Value <= y.Float64().Value {
+			return x
+		}
+		return y
+	})
+
+	merged := a.Merge(b, smaller)
+	expected, err := NewJson(`{"price": 7, "stock": {"count": 3, "min": 1}, "same": 5, "kind": "x"}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(merged), merged.Pretty())
+	assert.ElementsMatch(t, []string{"/price", "/stock/count", "/drop", "/kind"}, conflicts)
+
+	merged = a.Merge(b)
+	assert.Equal(t, 2, merged.K("drop").Int().Value)
+	assert.Equal(t, 8, merged.K("stock").K("count").Int().Value)
+}
+
+func TestMergeAll(t *testing.T) {
+	base, err := NewJson(`{"host": "localhost", "port": 80, "db": {"name": "app", "pool": 5}, "debug": false}`)
+	require.NoError(t, err)
+	env, err := NewJson(`{"host": "prod.example.com", "db": {"pool": 20}}`)
+	require.NoError(t, err)
+	flags, err := NewJson(`{"port": 8080, "db": {"pool": 50}, "verbose": true}`)
+	require.NoError(t, err)
+
+	merged, err := MergeAll([]Json{base, env, Json{}, flags})
+	require.NoError(t, err)
+	expected, err := NewJson(`{
+		"host": "prod.example.com",
+		"port": 8080,
+		"db": {"name": "app", "pool": 50},
+		"debug": false,
+		"verbose": true
+	}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(merged), merged.Pretty())
+	assert.Equal(t, 5, base.K("db").K("pool").Int().Value)
+
+	var conflicts []string
+	keepFirst := WithConflictHandler(func(pointer string, a, b Json) Json {
+		conflicts = append(conflicts, pointer)
+		return a
+	})
+	merged, err = MergeAll([]Json{base, env, flags}, keepFirst)
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", merged.K("host").String().Value)
+	assert.Equal(t, 5, merged.K("db").K("pool").Int().Value)
+	assert.ElementsMatch(t, []string{"/host", "/db/pool", "/db/pool", "/port"}, conflicts)
+
+	merged, err = MergeAll(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "{}", merged.Stringify())
+
+	_, err = MergeAll([]Json{base, base.K("host")})
+	assert.EqualError(t, err, "document 1 is not an object")
+}
+
+func TestMergeAllTracked(t *testing.T) {
+	defaults, err := NewJson(`{"host": "localhost", "port": 80, "db": {"name": "app", "pool": 5, "opts": {}}, "tags": ["a"]}`)
+	require.NoError(t, err)
+	prod, err := NewJson(`{"host": "prod.example.com", "db": {"pool": 20}, "tags": ["b", "c"]}`)
+	require.NoError(t, err)
+	local, err := NewJson(`{"db": {"pool": 20, "name": {"primary": "x"}}, "debug": true}`)
+	require.NoError(t, err)
+
+	merged, provenance, err := MergeAllTracked([]NamedJson{
+		{"defaults.json", defaults},
+		{"prod.json", prod},
+		{"missing.json", Json{}},
+		{"local.json", local},
+	})
+	require.NoError(t, err)
+
+	expected, err := MergeAll([]Json{defaults, prod, local})
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(merged), merged.Pretty())
+
+	assert.Equal(t, map[string]string{
+		"/host":            "prod.json",
+		"/port":            "defaults.json",
+		"/db/name/primary": "local.json",
+		"/db/pool":         "local.json",
+		"/db/opts":         "defaults.json",
+		"/tags":            "prod.json",
+		"/debug":           "local.json",
+	}, provenance)
+
+	_, provenance, err = MergeAllTracked([]NamedJson{{"defaults.json", defaults}, {"prod.json", prod}},
+		WithConflictHandler(func(pointer string, a, b Json) Json {
+			if pointer == "/port" || pointer == "/host" {
+				return a
+			}
+			return Json{data: "invented", exists: true}
+		}))
+	require.NoError(t, err)
+	assert.Equal(t, "defaults.json", provenance["/host"])
+	assert.NotContains(t, provenance, "/tags")
+
+	_, _, err = MergeAllTracked([]NamedJson{{"defaults.json", defaults}, {"bad.json", defaults.K("port")}})
+	assert.EqualError(t, err, `source "bad.json" is not an object`)
+}
+
+func TestMerge3Report(t *testing.T) {
+	base, err := NewJson(`{"db": {"host": "localhost", "port": 5432}, "tags": ["a"], "debug": false, "name": "app"}`)
+	require.NoError(t, err)
+	mine, err := NewJson(`{"db": {"host": "db.mine", "port": 5433}, "tags": ["a", "b"], "debug": true, "name": "app", "owner": "me"}`)
+	require.NoError(t, err)
+	theirs, err := NewJson(`{"db": {"host": "localhost", "port": 6543}, "tags": ["c"], "debug": true, "owner": "them"}`)
+	require.NoError(t, err)
+
+	expected := `<<<<<<< mine "/db/port"
+5433
+||||||| base
+5432
+=======
+6543
+>>>>>>> theirs
+
+<<<<<<< mine "/owner"
+"me"
+||||||| base
+(missing)
+=======
+"them"
+>>>>>>> theirs
+
+<<<<<<< mine "/tags"
+["a","b"]
+||||||| base
+["a"]
+=======
+["c"]
+>>>>>>> theirs
+`
+	assert.Equal(t, expected, Merge3Report(base, mine, theirs))
+}
+
+func TestMerge3ReportNoConflicts(t *testing.T) {
+	base, err := NewJson(`{"a": 1, "b": {"c": 2}}`)
+	require.NoError(t, err)
+	mine, err := NewJson(`{"a": 2, "b": {"c": 2}}`)
+	require.NoError(t, err)
+	theirs, err := NewJson(`{"a": 1, "b": {"c": 3, "d": 4}}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "", Merge3Report(base, mine, theirs))
+	assert.Equal(t, "", Merge3Report(base, mine, mine))
+	assert.Equal(t, "", Merge3Report(base, base, base))
+}
+
+func TestMerge3ReportNonObjects(t *testing.T) {
+	base, err := NewJson(`{"a": {"b": 1}}`)
+	require.NoError(t, err)
+	mine, err := NewJson(`{"a": "flat"}`)
+	require.NoError(t, err)
+	theirs, err := NewJson(`[1]`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "<<<<<<< mine \"\"\n{\"a\":\"flat\"}\n||||||| base\n{\"a\":{\"b\":1}}\n=======\n[1]\n>>>>>>> theirs\n",
+		Merge3Report(base, mine, theirs))
+
+	theirs, err = NewJson(`{"a": {"b": 2}}`)
+	require.NoError(t, err)
+	assert.Contains(t, Merge3Report(base, mine, theirs), "<<<<<<< mine \"/a\"\n\"flat\"\n||||||| base\n{\"b\":1}\n")
+}
@@ -0,0 +1,58 @@
+package jsn
+
+import "fmt"
+
+// The Must accessors return the bare value of a leaf, and panic if the matching accessor's
+// result isn't valid, e.g. MustString panics where String().IsValid is false.
+// They're for code that treats invalid data as a bug, like test setup or tools working on
+// data they control; use the {Value, IsValid} accessors for anything else.
+// The panic message names the expected type and describes the actual value (see Explain).
+
+// MustString returns the string value, or panics if this isn't a string
+func (j Json) MustString() string {
+	v := j.String()
+	if !v.IsValid {
+		j.mustPanic("MustString", KindString)
+	}
+	return v.Value
+}
+
+// MustInt returns the number as an int (truncated like Int), or panics if this isn't a number
+func (j Json) MustInt() int {
+	v := j.Int()
+	if !v.IsValid {
+		j.mustPanic("MustInt", KindNumber)
+	}
+	return v.Value
+}
+
+// MustInt64 returns the number as an int64 (truncated like Int64), or panics if this isn't a number
+func (j Json) MustInt64() int64 {
+	v := j.Int64()
+	if !v.IsValid {
+		j.mustPanic("MustInt64", KindNumber)
+	}
+	return v.Value
+}
+
+// MustFloat64 returns the number as a float64, or panics if this isn't a number
+func (j Json) MustFloat64() float64 {
+	v := j.Float64()
+	if !v.IsValid {
+		j.mustPanic("MustFloat64", KindNumber)
+	}
+	return v.Value
+}
+
+// MustBool returns the boolean value, or panics if this isn't a boolean
+func (j Json) MustBool() bool {
+	v := j.Bool()
+	if !v.IsValid {
+		j.mustPanic("MustBool", KindBool)
+	}
+	return v.Value
+}
+
+func (j Json) mustPanic(accessor string, expected Kind) {
+	panic(fmt.Sprintf("jsn: %s: expected %s, got %s", accessor, expected, j.Explain()))
+}
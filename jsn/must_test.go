@@ -0,0 +1,31 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMust(t *testing.T) {
+	j, err := NewJson(`{"s": "gopher", "i": 42, "big": 9007199254740993, "f": 2.5, "b": true}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "gopher", j.K("s").MustString())
+	assert.Equal(t, 42, j.K("i").MustInt())
+	assert.Equal(t, int64(9007199254740993), j.K("big").MustInt64())
+	assert.Equal(t, 2.5, j.K("f").MustFloat64())
+	assert.Equal(t, 2, j.K("f").MustInt())
+	assert.Equal(t, true, j.K("b").MustBool())
+}
+
+func TestMustPanics(t *testing.T) {
+	j, err := NewJson(`{"s": "gopher", "i": 42, "n": null, "o": {"a": 1}}`)
+	require.NoError(t, err)
+
+	assert.PanicsWithValue(t, "jsn: MustString: expected string, got number 42", func() { j.K("i").MustString() })
+	assert.PanicsWithValue(t, "jsn: MustInt: expected number, got string of length 6", func() { j.K("s").MustInt() })
+	assert.PanicsWithValue(t, "jsn: MustInt64: expected number, got null", func() { j.K("n").MustInt64() })
+	assert.PanicsWithValue(t, "jsn: MustFloat64: expected number, got object with 1 key (a)", func() { j.K("o").MustFloat64() })
+	assert.PanicsWithValue(t, "jsn: MustBool: expected bool, got undefined", func() { j.K("missing").MustBool() })
+}
@@ -0,0 +1,293 @@
+package jsn
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Set stores value under key in this object, replacing any previous value.
+// value is a Json, whose data is deep-copied, or any json.Marshal-able Go value, which is
+// marshaled and decoded back, so a Go map or slice is copied, not shared.
+// Unlike NewJson, JSON text isn't parsed: a string is stored as a JSON string, a []byte
+// as its base64 string, and an io.Reader is marshaled like any struct (usually to {}).
+// An undefined receiver becomes a new object holding the key.
+// The object is modified in place: the change is visible through every Json sharing it,
+// like copies of the receiver, or the parent it was obtained from with Get.
+// Returns an error if the receiver is defined but isn't an object (e.g. an array or null),
+// or if value can't be converted to JSON.
+func (j *Json) Set(key string, value interface{}) error {
+	m, ok := j.asMap()
+	if !ok {
+		if j.exists {
+			return errors.New("value is not an object")
+		}
+		m = map[string]interface{}{}
+	}
+
+	v, err := normalize(value)
+	if err != nil {
+		return err
+	}
+
+	m[key] = v
+	j.data = m
+	j.exists = true
+
+	return nil
+}
+
+// SetPath stores value at a dotted path, with the syntax of GetPath, creating the missing
+// intermediate values: an object for a key, or an array for an index, padded with nulls up
+// to the index. SetPath("a.b[2].c", 5) on an empty object gives {"a": {"b": [null, null, {"c": 5}]}}.
+// A null along the path is replaced the same way, but any other value of the wrong type
+// blocks the traversal and makes SetPath fail with an error naming the path up to it,
+// leaving the document unchanged. An empty path replaces the whole value.
+// As with Set, value is a Json or a json.Marshal-able Go value (JSON text isn't parsed),
+// and existing objects are modified in place.
+func (j *Json) SetPath(path string, value interface{}) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	v, err := normalize(value)
+	if err != nil {
+		return err
+	}
+
+	var data interface{}
+	if j.exists {
+		data = j.data
+	}
+	data, err = setSegments(data, segments, 0, v)
+	if err != nil {
+		return err
+	}
+
+	j.data = data
+	j.exists = true
+
+	return nil
+}
+
+// setSegments stores value under segments[done:] of data, and returns the updated data
+func setSegments(data interface{}, segments []pathSegment, done int, value interface{}) (interface{}, error) {
+	if done == len(segments) {
+		return value, nil
+	}
+	s := segments[done]
+
+	blocked := func(container Kind) error {
+		kind := Json{data: data, exists: true}.Type()
+		return fmt.Errorf("%q is %s %s, not %s %s",
+			formatPath(segments[:done]), article(kind), kind, article(container), container)
+	}
+
+	if s.isIndex {
+		a, ok := data.([]interface{})
+		if !ok && data != nil {
+			return nil, blocked(KindArray)
+		}
+		for len(a) <= s.index {
+			a = append(a, nil)
+		}
+
+		e, err := setSegments(a[s.index], segments, done+1, value)
+		if err != nil {
+			return nil, err
+		}
+		a[s.index] = e
+		return a, nil
+	}
+
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		if data != nil {
+			return nil, blocked(KindObject)
+		}
+		m = map[string]interface{}{}
+	}
+
+	e, err := setSegments(m[s.key], segments, done+1, value)
+	if err != nil {
+		return nil, err
+	}
+	m[s.key] = e
+	return m, nil
+}
+
+// Delete removes key from this object, and returns whether it was there.
+// The object is modified in place, like with Set.
+// Does nothing and returns false if this isn't an object.
+func (j *Json) Delete(key string) bool {
+	m, ok := j.asMap()
+	if !ok {
+		return false
+	}
+
+	_, exists := m[key]
+	delete(m, key)
+
+	return exists
+}
+
+// DeletePath removes the value at a dotted path, with the syntax of GetPath, and returns
+// whether it was there. Removing an array element shifts the following ones down, as a new
+// array replacing the old one in its parent; objects are modified in place.
+// Does nothing and returns false if the path is missing or malformed, or if it's empty.
+func (j *Json) DeletePath(path string) bool {
+	segments, err := parsePath(path)
+	if err != nil || len(segments) == 0 || !j.exists {
+		return false
+	}
+
+	data, deleted := deleteSegments(j.data, segments)
+	if deleted {
+		j.data = data
+	}
+
+	return deleted
+}
+
+// deleteSegments removes the value under segments from data, and returns the updated data
+func deleteSegments(data interface{}, segments []pathSegment) (interface{}, bool) {
+	s, last := segments[0], len(segments) == 1
+
+	if s.isIndex {
+		a, ok := data.([]interface{})
+		if !ok || s.index >= len(a) {
+			return data, false
+		}
+
+		if last {
+			shorter := make([]interface{}, 0, len(a)-1)
+			return append(append(shorter, a[:s.index]...), a[s.index+1:]...), true
+		}
+
+		e, deleted := deleteSegments(a[s.index], segments[1:])
+		if deleted {
+			a[s.index] = e
+		}
+		return a, deleted
+	}
+
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return data, false
+	}
+	v, exists := m[s.key]
+	if !exists {
+		return data, false
+	}
+
+	if last {
+		delete(m, s.key)
+		return m, true
+	}
+
+	e, deleted := deleteSegments(v, segments[1:])
+	if deleted {
+		m[s.key] = e
+	}
+	return m, deleted
+}
+
+// GetOrCreateObject returns the object under key, first creating it as an empty object if
+// the key is missing or null, so nested objects can be built step by step:
+//
+//	db, _ := j.GetOrCreateObject("db")
+//	db.Set("host", "localhost") // j is now {"db": {"host": "localhost"}}
+//
+// The receiver is modified in place, like with Set, and an undefined receiver becomes an object.
+// The returned Json shares its data with the receiver, so setting keys on it shows in the receiver.
+// Returns an error if the receiver isn't an object, or if key holds something else than an object.
+func (j *Json) GetOrCreateObject(key string) (Json, error) {
+	return j.getOrCreate(key, KindObject, map[string]interface{}{})
+}
+
+// GetOrCreateArray is like GetOrCreateObject, for an array under key.
+// Growing the returned array (e.g. with SetPath) doesn't show in the receiver, since it gives
+// a new array; use SetPath on the receiver to append elements.
+func (j *Json) GetOrCreateArray(key string) (Json, error) {
+	return j.getOrCreate(key, KindArray, []interface{}{})
+}
+
+func (j *Json) getOrCreate(key string, kind Kind, empty interface{}) (Json, error) {
+	m, ok := j.asMap()
+	if !ok {
+		if j.exists {
+			return Json{}, errors.New("value is not an object")
+		}
+		m = map[string]interface{}{}
+		j.data = m
+		j.exists = true
+	}
+
+	v := j.Get(key)
+	switch v.Type() {
+	case kind:
+		return v, nil
+	case KindInvalid, KindNull:
+		m[key] = empty
+		return j.Get(key), nil
+	default:
+		return Json{}, fmt.Errorf("key %q holds %s %s, not %s %s", key, article(v.Type()), v.Type(), article(kind), kind)
+	}
+}
+
+// ErrConflict is returned (wrapped) by TestAndSet when the current value isn't the expected one
+var ErrConflict = errors.New("conflict")
+
+// TestAndSet is a compare-and-swap on a JSON Pointer (RFC 6901), for optimistic concurrency:
+// if the value at pointer deep-equals expected (compared like Equal), it returns a clone of
+// the document with newValue set there; the receiver is never modified.
+// expected and newValue can be a Json or any json.Marshal-able value. An undefined Json as
+// expected matches a missing value, to set a key that must not exist yet (its parent must).
+// When the current value differs, the returned error wraps ErrConflict
+// (check it with errors.Is) and names the pointer and both values.
+// Other errors are returned for a malformed pointer, a location that can't be set,
+// or values that can't be converted to JSON.
+func (j Json) TestAndSet(pointer string, expected interface{}, newValue interface{}) (Json, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return Json{}, err
+	}
+
+	want, ok := expected.(Json)
+	if !ok {
+		data, err := normalize(expected)
+		if err != nil {
+			return Json{}, err
+		}
+		want = Json{data: data, exists: true}
+	}
+	value, err := normalize(newValue)
+	if err != nil {
+		return Json{}, err
+	}
+
+	current, err := j.resolvePointer(pointer)
+	if err != nil {
+		return Json{}, err
+	}
+	if !current.Equal(want) {
+		return Json{}, fmt.Errorf("%q: %w: expected %s, got %s", pointer, ErrConflict, describe(want), describe(current))
+	}
+
+	clone := j.Clone()
+	clone.data, err = setPointer(clone.data, tokens, value)
+	if err != nil {
+		return Json{}, fmt.Errorf("%q: %v", pointer, err)
+	}
+	clone.exists = true
+
+	return clone, nil
+}
+
+// describe formats a value for an error message
+func describe(j Json) string {
+	if !j.exists {
+		return "nothing"
+	}
+	return j.Stringify()
+}
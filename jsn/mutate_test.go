@@ -0,0 +1,229 @@
+package jsn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet(t *testing.T) {
+	var j Json
+	require.NoError(t, j.Set("name", "svc"))
+	require.NoError(t, j.Set("port", 8080))
+	require.NoError(t, j.Set("tags", []string{"a", "b"}))
+	require.NoError(t, j.Set("limits", Map{"cpu": 1.5}))
+	require.NoError(t, j.Set("none", nil))
+	assert.Equal(t, `{"limits":{"cpu":1.5},"name":"svc","none":null,"port":8080,"tags":["a","b"]}`, j.Stringify())
+
+	require.NoError(t, j.Set("port", "http"))
+	assert.Equal(t, "http", j.K("port").String().Value)
+
+	// JSON text isn't parsed, unlike with NewJson
+	var raw Json
+	require.NoError(t, raw.Set("text", `{"x": 1}`))
+	require.NoError(t, raw.Set("bytes", []byte("{}")))
+	assert.Equal(t, `{"bytes":"e30=","text":"{\"x\": 1}"}`, raw.Stringify())
+
+	// a Json value is copied
+	nested, err := NewJson(`{"x": 1}`)
+	require.NoError(t, err)
+	require.NoError(t, j.Set("nested", nested))
+	require.NoError(t, nested.Set("x", 2))
+	assert.Equal(t, 1, j.K("nested").K("x").Int().Value)
+
+	// children share the receiver's data
+	limits := j.K("limits")
+	require.NoError(t, limits.Set("mem", 512))
+	assert.Equal(t, 512, j.K("limits").K("mem").Int().Value)
+
+	back, err := NewJson(j.Stringify())
+	require.NoError(t, err)
+	assert.True(t, back.Equal(j), back.Stringify())
+}
+
+func TestSetErrors(t *testing.T) {
+	j, err := NewJson(`{"list": [1], "null": null}`)
+	require.NoError(t, err)
+
+	list := j.K("list")
+	assert.EqualError(t, list.Set("a", 1), "value is not an object")
+	assert.Equal(t, "[1]", list.Stringify())
+
+	null := j.K("null")
+	assert.EqualError(t, null.Set("a", 1), "value is not an object")
+
+	assert.Error(t, j.Set("bad", make(chan int)))
+	assert.False(t, j.Exists("bad"))
+}
+
+func TestSetPath(t *testing.T) {
+	j, err := NewJson(`{}`)
+	require.NoError(t, err)
+
+	require.NoError(t, j.SetPath("a.b[2].c", 5))
+	assert.Equal(t, `{"a":{"b":[null,null,{"c":5}]}}`, j.Stringify())
+
+	require.NoError(t, j.SetPath("a.b[0]", "first"))
+	require.NoError(t, j.SetPath("a.b[2].d", true))
+	require.NoError(t, j.SetPath(`a.x\.y`, Map{"z": 1}))
+	require.NoError(t, j.SetPath("a.b[1][1]", 2))
+	assert.Equal(t, `{"a":{"b":["first",[null,2],{"c":5,"d":true}],"x.y":{"z":1}}}`, j.Stringify())
+
+	var undefined Json
+	require.NoError(t, undefined.SetPath("[1].k", "v"))
+	assert.Equal(t, `[null,{"k":"v"}]`, undefined.Stringify())
+
+	require.NoError(t, undefined.SetPath("", "whole"))
+	assert.Equal(t, `"whole"`, undefined.Stringify())
+}
+
+func TestSetPathErrors(t *testing.T) {
+	j, err := NewJson(`{"a": {"name": "x", "list": [1], "dot.ted": 3}}`)
+	require.NoError(t, err)
+	before := j.Stringify()
+
+	assert.EqualError(t, j.SetPath("a.name.first", 1), `"a.name" is a string, not an object`)
+	assert.EqualError(t, j.SetPath("a.list.first", 1), `"a.list" is an array, not an object`)
+	assert.EqualError(t, j.SetPath("a[0]", 1), `"a" is an object, not an array`)
+	assert.EqualError(t, j.SetPath(`a.dot\.ted[0]`, 1), `"a.dot\\.ted" is a number, not an array`)
+	assert.EqualError(t, j.SetPath("a.list[0].x", 1), `"a.list[0]" is a number, not an object`)
+	assert.EqualError(t, j.SetPath("a[", 1), `invalid path "a[": unclosed bracket`)
+	assert.Error(t, j.SetPath("a.b", make(chan int)))
+	assert.Equal(t, before, j.Stringify())
+}
+
+func TestDelete(t *testing.T) {
+	j, err := NewJson(`{"user": "alice", "password": "x", "nested": {"token": "t"}}`)
+	require.NoError(t, err)
+
+	assert.True(t, j.Delete("password"))
+	assert.False(t, j.Delete("password"))
+	assert.Equal(t, `{"nested":{"token":"t"},"user":"alice"}`, j.Stringify())
+
+	nested := j.K("nested")
+	assert.True(t, nested.Delete("token"))
+	assert.Equal(t, `{"nested":{},"user":"alice"}`, j.Stringify())
+
+	user := j.K("user")
+	assert.False(t, user.Delete("x"))
+	var undefined Json
+	assert.False(t, undefined.Delete("x"))
+}
+
+func TestDeletePath(t *testing.T) {
+	j, err := NewJson(`{"req": {"headers": {"Authorization": "secret", "Accept": "*/*"}, "items": [{"card": "1234"}, "b", "c"]}, "a.b": 1}`)
+	require.NoError(t, err)
+	items := j.GetPath("req.items")
+
+	assert.True(t, j.DeletePath("req.headers.Authorization"))
+	assert.True(t, j.DeletePath("req.items[0].card"))
+	assert.True(t, j.DeletePath("req.items[1]"))
+	assert.True(t, j.DeletePath(`a\.b`))
+	assert.Equal(t, `{"req":{"headers":{"Accept":"*/*"},"items":[{},"c"]}}`, j.Stringify())
+	assert.Equal(t, `[{},"b","c"]`, items.Stringify(), "removing an element replaces the array")
+
+	assert.False(t, j.DeletePath("req.headers.Authorization"))
+	assert.False(t, j.DeletePath("req.items[5]"))
+	assert.False(t, j.DeletePath("req.items.x"))
+	assert.False(t, j.DeletePath("req.headers[0]"))
+	assert.False(t, j.DeletePath("req.headers.Accept.x"))
+	assert.False(t, j.DeletePath("req["))
+	assert.False(t, j.DeletePath(""))
+	assert.Equal(t, `{"req":{"headers":{"Accept":"*/*"},"items":[{},"c"]}}`, j.Stringify())
+
+	var undefined Json
+	assert.False(t, undefined.DeletePath("a"))
+}
+
+func TestGetOrCreate(t *testing.T) {
+	var j Json
+	db, err := j.GetOrCreateObject("db")
+	require.NoError(t, err)
+	require.NoError(t, db.Set("host", "localhost"))
+
+	pool, err := db.GetOrCreateObject("pool")
+	require.NoError(t, err)
+	require.NoError(t, pool.Set("size", 10))
+
+	_, err = j.GetOrCreateArray("replicas")
+	require.NoError(t, err)
+	assert.Equal(t, `{"db":{"host":"localhost","pool":{"size":10}},"replicas":[]}`, j.Stringify())
+
+	again, err := j.GetOrCreateObject("db")
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", again.K("host").String().Value)
+
+	existing, err := NewJson(`{"list": [1], "none": null, "name": "x"}`)
+	require.NoError(t, err)
+	list, err := existing.GetOrCreateArray("list")
+	require.NoError(t, err)
+	assert.Equal(t, "[1]", list.Stringify())
+
+	none, err := existing.GetOrCreateObject("none")
+	require.NoError(t, err)
+	require.NoError(t, none.Set("a", true))
+	assert.Equal(t, `{"a":true}`, existing.K("none").Stringify())
+
+	_, err = existing.GetOrCreateObject("name")
+	assert.EqualError(t, err, `key "name" holds a string, not an object`)
+	_, err = existing.GetOrCreateArray("none")
+	assert.EqualError(t, err, `key "none" holds an object, not an array`)
+	_, err = list.GetOrCreateObject("x")
+	assert.EqualError(t, err, "value is not an object")
+}
+
+func TestTestAndSet(t *testing.T) {
+	j, err := NewJson(`{"version": 3, "spec": {"replicas": 2, "ports": [80]}}`)
+	require.NoError(t, err)
+
+	updated, err := j.TestAndSet("/version", 3, 4)
+	require.NoError(t, err)
+	assert.Equal(t, `{"spec":{"ports":[80],"replicas":2},"version":4}`, updated.Stringify())
+	assert.Equal(t, 3, j.K("version").Int().Value, "receiver must not be modified")
+
+	updated, err = j.TestAndSet("/spec", Map{"ports": []int{80}, "replicas": 2.0}, Map{"replicas": 5})
+	require.NoError(t, err)
+	assert.Equal(t, `{"spec":{"replicas":5},"version":3}`, updated.Stringify())
+
+	updated, err = j.TestAndSet("/spec/ports/1", Json{}, 443)
+	require.NoError(t, err)
+	assert.Equal(t, `[80,443]`, updated.K("spec").K("ports").Stringify())
+
+	updated, err = j.TestAndSet("/spec/owner", Json{}, "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", updated.K("spec").K("owner").String().Value)
+
+	updated, err = j.TestAndSet("", j, "replaced")
+	require.NoError(t, err)
+	assert.Equal(t, `"replaced"`, updated.Stringify())
+}
+
+func TestTestAndSetConflict(t *testing.T) {
+	j, err := NewJson(`{"version": 3, "spec": {"replicas": 2}}`)
+	require.NoError(t, err)
+
+	updated, err := j.TestAndSet("/version", 2, 3)
+	assert.True(t, errors.Is(err, ErrConflict))
+	assert.EqualError(t, err, `"/version": conflict: expected 2, got 3`)
+	assert.True(t, updated.Undefined())
+
+	_, err = j.TestAndSet("/spec/owner", "team-a", "team-b")
+	assert.True(t, errors.Is(err, ErrConflict))
+	assert.EqualError(t, err, `"/spec/owner": conflict: expected "team-a", got nothing`)
+
+	_, err = j.TestAndSet("/version", Json{}, 4)
+	assert.EqualError(t, err, `"/version": conflict: expected nothing, got 3`)
+
+	_, err = j.TestAndSet("/spec/x/y", Json{}, 1)
+	assert.False(t, errors.Is(err, ErrConflict))
+	assert.EqualError(t, err, `"/spec/x/y": key "x" not found`)
+
+	_, err = j.TestAndSet("version", 3, 4)
+	assert.EqualError(t, err, `invalid JSON pointer "version": must start with '/'`)
+
+	_, err = j.TestAndSet("/version", 3, make(chan int))
+	assert.Error(t, err)
+	assert.Equal(t, 3, j.K("version").Int().Value)
+}
@@ -0,0 +1,58 @@
+package jsn
+
+import "strconv"
+
+// observer reports accesses to the values of a document created by NewJsonObserved.
+// it's carried by every Json reached from the root, along with that value's JSON Pointer
+type observer struct {
+	f       func(pointer string)
+	pointer string
+}
+
+// NewJsonObserved is like NewJson, but f is called with the JSON Pointer (RFC 6901) of every
+// value accessed via Get/K/I (or any accessor built on them) on the result or on values reached
+// from it, including accesses to missing values.
+// Comparing the recorded pointers with the document shows which fields your code actually
+// reads, and which ones it expects but are missing.
+// Values reached via IterMap keep being observed, but aren't reported by the iteration itself.
+// Values reached via Array().Elements() are not observed.
+// This is a diagnostic tool: observed documents are slower to access, as every access builds
+// a pointer string and calls f.
+func NewJsonObserved(src interface{}, f func(pointer string)) (Json, error) {
+	j, err := NewJson(src)
+	if err != nil {
+		return Json{}, err
+	}
+
+	j.obs = &observer{f: f}
+	return j, nil
+}
+
+// keyChild wraps the value under key, carrying on the observer if there's one
+func (j Json) keyChild(key string, data interface{}, exists bool) Json {
+	c := Json{data: data, exists: exists}
+	if j.obs != nil {
+		c.obs = &observer{j.obs.f, j.obs.pointer + "/" + escapePointerToken(key)}
+	}
+
+	return c
+}
+
+// indexChild wraps the array element at index, carrying on the observer if there's one
+func (j Json) indexChild(index int, data interface{}, exists bool) Json {
+	c := Json{data: data, exists: exists}
+	if j.obs != nil {
+		c.obs = &observer{j.obs.f, j.obs.pointer + "/" + strconv.Itoa(index)}
+	}
+
+	return c
+}
+
+// observe reports the access to this value, if it's observed
+func (j Json) observe() Json {
+	if j.obs != nil {
+		j.obs.f(j.obs.pointer)
+	}
+
+	return j
+}
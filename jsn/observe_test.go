@@ -0,0 +1,55 @@
+package jsn
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJsonObserved(t *testing.T) {
+	var accessed []string
+	j, err := NewJsonObserved(`{
+		"server": {"host": "localhost", "ports": [80, 443]},
+		"a/b": true,
+		"unused": 1
+	}`, func(pointer string) {
+		accessed = append(accessed, pointer)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "localhost", j.K("server").K("host").String().Value)
+	assert.Equal(t, 443, j.K("server").K("ports").I(1).Int().Value)
+	assert.True(t, j.K("a/b").Bool().Value)
+	assert.True(t, j.K("missing").K("deeper").Undefined())
+	assert.True(t, j.K("server").K("ports").I(5).Undefined())
+
+	assert.Equal(t, []string{
+		"/server", "/server/host",
+		"/server", "/server/ports", "/server/ports/1",
+		"/a~1b",
+		"/missing", "/missing/deeper",
+		"/server", "/server/ports", "/server/ports/5",
+	}, accessed)
+
+	accessed = nil
+	j.K("server").IterMap(func(key string, v Json) bool {
+		if key == "ports" {
+			v.I(0)
+		}
+		return true
+	})
+	assert.Equal(t, []string{"/server", "/server/ports/0"}, accessed)
+
+	_, err = NewJsonObserved(`{broken`, func(string) {})
+	assert.Error(t, err)
+}
+
+func TestNotObserved(t *testing.T) {
+	j, err := NewJson(`{"a": [1]}`)
+	require.NoError(t, err)
+
+	assert.Nil(t, j.K("a").obs)
+	assert.Equal(t, Json{data: json.Number("1"), exists: true}, j.K("a").I(0))
+}
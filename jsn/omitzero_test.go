@@ -0,0 +1,35 @@
+//go:build go1.24
+
+package jsn
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encoding/json honors the omitzero tag, which calls IsZero, since Go 1.24
+func TestIsZeroOmitZero(t *testing.T) {
+	j, err := NewJson(`{"a": null, "b": 0, "d": {}}`)
+	require.NoError(t, err)
+
+	type Event struct {
+		Name    string `json:"name"`
+		Payload Json   `json:"payload,omitzero"`
+		Meta    Json   `json:"meta"`
+	}
+
+	bytes, err := json.Marshal(Event{Name: "e", Payload: j.K("no"), Meta: j.K("no")})
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"e","meta":null}`, string(bytes))
+
+	bytes, err = json.Marshal(Event{Name: "e", Payload: j.K("a")})
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"e","meta":null}`, string(bytes))
+
+	bytes, err = json.Marshal(Event{Name: "e", Payload: j.K("d"), Meta: j.K("b")})
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"e","payload":{},"meta":0}`, string(bytes))
+}
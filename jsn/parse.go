@@ -0,0 +1,130 @@
+package jsn
+
+import (
+	"errors"
+	"fmt"
+)
+
+// stripComments removes // line comments and /* block */ comments that are outside of strings,
+// turning JSONC (JSON with comments) into plain JSON
+func stripComments(src []byte) ([]byte, error) {
+	out := make([]byte, 0, len(src))
+	inString := false
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+
+		if inString {
+			out = append(out, c)
+			switch c {
+			case '\\':
+				if i+1 < len(src) {
+					i++
+					out = append(out, src[i])
+				}
+			case '"':
+				inString = false
+			}
+			continue
+		}
+
+		if c == '/' && i+1 < len(src) && src[i+1] == '/' {
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			if i < len(src) {
+				out = append(out, '\n')
+			}
+			continue
+		}
+
+		if c == '/' && i+1 < len(src) && src[i+1] == '*' {
+			end := -1
+			for k := i + 2; k+1 < len(src); k++ {
+				if src[k] == '*' && src[k+1] == '/' {
+					end = k + 1
+					break
+				}
+			}
+			if end < 0 {
+				return nil, errors.New("unterminated block comment")
+			}
+			out = append(out, ' ')
+			i = end
+			continue
+		}
+
+		if c == '"' {
+			inString = true
+		}
+		out = append(out, c)
+	}
+
+	return out, nil
+}
+
+type parseConfig struct {
+	comments bool
+}
+
+// ParseOption configures ParseStringField
+type ParseOption func(*parseConfig)
+
+// WithComments makes ParseStringField accept JSONC: // and /* */ comments outside of strings
+// are stripped from the embedded document before parsing
+func WithComments() ParseOption {
+	return func(c *parseConfig) {
+		c.comments = true
+	}
+}
+
+// ParseStringField parses the string value under key as a JSON document,
+// for doubly-encoded payloads like {"payload": "{\"a\": 1}"}.
+// The content must be strict JSON, unless WithComments is given.
+// Returns an error if this isn't a map, the key is missing or not a string, or the content doesn't parse.
+func (j Json) ParseStringField(key string, opts ...ParseOption) (Json, error) {
+	var c parseConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if _, ok := j.asMap(); !ok {
+		return Json{}, errors.New("value is not an object")
+	}
+
+	v := j.Get(key)
+	if !v.exists {
+		return Json{}, fmt.Errorf("key %q not found", key)
+	}
+	s, ok := v.data.(string)
+	if !ok {
+		return Json{}, fmt.Errorf("key %q is not a string", key)
+	}
+
+	src := []byte(s)
+	if c.comments {
+		var err error
+		if src, err = stripComments(src); err != nil {
+			return Json{}, fmt.Errorf("key %q: %v", key, err)
+		}
+	}
+
+	parsed, err := NewJson(src)
+	if err != nil {
+		return Json{}, fmt.Errorf("key %q: %v", key, err)
+	}
+
+	return parsed, nil
+}
+
+// AsJSON parses the content of this string value as a JSON document,
+// for doubly-encoded payloads: j.K("payload").AsJSON()
+// Returns an error if the value isn't a string or its content doesn't parse.
+func (j Json) AsJSON() (Json, error) {
+	s, ok := j.data.(string)
+	if !j.exists || !ok {
+		return Json{}, errors.New("value is not a string")
+	}
+
+	return NewJson(s)
+}
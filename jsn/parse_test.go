@@ -0,0 +1,94 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripComments(t *testing.T) {
+	out, err := stripComments([]byte(`{
+		// a line comment
+		"a": 1, /* block */ "b": "not // a comment",
+		"c": "escaped \" /* still a string */"
+	}`))
+	require.NoError(t, err)
+
+	j, err := NewJson(out)
+	require.NoError(t, err)
+	assert.Equal(t, 1, j.K("a").Int().Value)
+	assert.Equal(t, "not // a comment", j.K("b").String().Value)
+	assert.Equal(t, `escaped " /* still a string */`, j.K("c").String().Value)
+
+	_, err = stripComments([]byte(`{"a": 1} /* unterminated`))
+	assert.Error(t, err)
+}
+
+func TestParseStringField(t *testing.T) {
+	j, err := NewJson(`{
+		"payload": "{\"a\": 1, \"b\": [true]}",
+		"jsonc": "{\n// id of the thing\n\"id\": 7 /* seven */\n}",
+		"broken": "{\"a\": ",
+		"number": 12
+	}`)
+	require.NoError(t, err)
+
+	p, err := j.ParseStringField("payload")
+	require.NoError(t, err)
+	assert.Equal(t, 1, p.K("a").Int().Value)
+	assert.True(t, p.K("b").I(0).Bool().Value)
+
+	_, err = j.ParseStringField("jsonc")
+	assert.Error(t, err, "comments are rejected by default")
+
+	p, err = j.ParseStringField("jsonc", WithComments())
+	require.NoError(t, err)
+	assert.Equal(t, 7, p.K("id").Int().Value)
+
+	p, err = j.ParseStringField("payload", WithComments())
+	require.NoError(t, err)
+	assert.Equal(t, 1, p.K("a").Int().Value)
+
+	_, err = j.ParseStringField("broken")
+	assert.Error(t, err)
+	_, err = j.ParseStringField("broken", WithComments())
+	assert.Error(t, err)
+
+	_, err = j.ParseStringField("number")
+	assert.EqualError(t, err, `key "number" is not a string`)
+
+	_, err = j.ParseStringField("no")
+	assert.EqualError(t, err, `key "no" not found`)
+
+	_, err = j.K("number").ParseStringField("x")
+	assert.EqualError(t, err, "value is not an object")
+}
+
+func TestAsJSON(t *testing.T) {
+	j, err := NewJson(`{
+		"payload": "{\"a\": {\"b\": [1, 2]}}",
+		"scalar": "\"quoted\"",
+		"broken": "{\"a\": ",
+		"number": 12
+	}`)
+	require.NoError(t, err)
+
+	p, err := j.K("payload").AsJSON()
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.K("a").K("b").I(1).Int().Value)
+
+	p, err = j.K("scalar").AsJSON()
+	require.NoError(t, err)
+	assert.Equal(t, "quoted", p.String().Value)
+
+	p, err = j.K("broken").AsJSON()
+	assert.Error(t, err)
+	assert.True(t, p.Undefined())
+
+	_, err = j.K("number").AsJSON()
+	assert.EqualError(t, err, "value is not a string")
+
+	_, err = j.K("no").AsJSON()
+	assert.EqualError(t, err, "value is not a string")
+}
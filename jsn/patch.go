@@ -0,0 +1,63 @@
+package jsn
+
+import "errors"
+
+// MergePatch returns a copy of this Json with an RFC 7386 JSON merge patch applied:
+// when patch is an object, every key set to null is deleted, objects are patched
+// recursively, and any other value (including an array) replaces the target's value.
+// A patch that isn't an object replaces the whole document.
+// Unlike Merge, a null in the patch deletes the key rather than setting it to null.
+// The result shares no state with this Json or the patch. An undefined patch changes nothing.
+func (j Json) MergePatch(patch Json) Json {
+	if !patch.exists {
+		return Json{data: deepCopy(j.data), exists: j.exists}
+	}
+
+	return Json{data: mergePatchInto(deepCopy(j.data), patch.data), exists: true}
+}
+
+// MergePatchInto applies an RFC 7386 JSON merge patch to this Json in place:
+// when patch is an object, every key set to null is deleted, every other key is patched
+// recursively (a non-object replaces the value wholesale), and when patch isn't an object,
+// it replaces the whole value.
+// Nested objects of the receiver are mutated rather than copied, so the change is visible
+// through every Json sharing them: copies of the receiver and values obtained from it with
+// Get or I. Values taken from patch are copied, so the patch can be reused afterwards.
+// When the receiver (or a nested value) is replaced rather than patched, values previously
+// obtained from it keep pointing at the old data.
+// Returns an error if patch is undefined.
+func (j *Json) MergePatchInto(patch Json) error {
+	if !patch.exists {
+		return errors.New("patch is undefined")
+	}
+
+	j.data = mergePatchInto(j.data, patch.data)
+	j.exists = true
+
+	return nil
+}
+
+// mergePatchInto applies patch to target, mutating target's maps in place when
+// both are objects, and returns the patched value.
+// the parts of the result coming from patch are copies
+func mergePatchInto(target, patch interface{}) interface{} {
+	pm, ok := patch.(map[string]interface{})
+	if !ok {
+		return deepCopy(patch)
+	}
+
+	tm, ok := target.(map[string]interface{})
+	if !ok {
+		tm = map[string]interface{}{}
+	}
+
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+			continue
+		}
+		tm[k] = mergePatchInto(tm[k], v)
+	}
+
+	return tm
+}
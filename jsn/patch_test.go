@@ -0,0 +1,110 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatch(t *testing.T) {
+	// the examples of RFC 7386, Appendix A
+	tests := []struct {
+		target, patch, result string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		target, err := NewJson(tt.target)
+		require.NoError(t, err)
+		patch, err := NewJson(tt.patch)
+		require.NoError(t, err)
+
+		patched := target.MergePatch(patch)
+		assert.Equal(t, tt.result, patched.Stringify(), "%s patched with %s", tt.target, tt.patch)
+		assert.Equal(t, tt.target, target.Stringify(), "the target is left unchanged")
+	}
+}
+
+func TestMergePatchCopies(t *testing.T) {
+	j, err := NewJson(`{"a": {"b": 1}, "c": [1]}`)
+	require.NoError(t, err)
+	patch, err := NewJson(`{"a": {"d": {"e": 2}}}`)
+	require.NoError(t, err)
+
+	patched := j.MergePatch(patch)
+	assert.Equal(t, `{"a":{"b":1,"d":{"e":2}},"c":[1]}`, patched.Stringify())
+
+	a := patched.K("a")
+	require.NoError(t, a.Set("b", 10))
+	patched.K("a").K("d").Raw().(map[string]interface{})["e"] = 20
+	patched.K("c").Raw().([]interface{})[0] = 10
+	assert.Equal(t, `{"a":{"b":1},"c":[1]}`, j.Stringify())
+	assert.Equal(t, `{"a":{"d":{"e":2}}}`, patch.Stringify())
+
+	assert.True(t, j.Equal(j.MergePatch(Json{})))
+	assert.True(t, Json{}.MergePatch(Json{}).Undefined())
+	assert.Equal(t, `{"a":{"d":{"e":2}}}`, Json{}.MergePatch(patch).Stringify())
+}
+
+func TestMergePatchInto(t *testing.T) {
+	j, err := NewJson(`{"title": "Hello", "author": {"given": "John", "family": "Doe"}, "tags": ["a", "b"], "content": "x"}`)
+	require.NoError(t, err)
+	author := j.K("author")
+	alias := j
+
+	patch, err := NewJson(`{"title": "Bye", "author": {"family": null, "nick": "jd"}, "tags": ["c"], "phone": {"home": "1"}}`)
+	require.NoError(t, err)
+	require.NoError(t, j.MergePatchInto(patch))
+
+	expected, err := NewJson(`{"title": "Bye", "author": {"given": "John", "nick": "jd"}, "tags": ["c"], "content": "x", "phone": {"home": "1"}}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(j), j.Pretty())
+
+	// mutated in place
+	assert.True(t, expected.Equal(alias), alias.Pretty())
+	assert.Equal(t, "jd", author.K("nick").String().Value)
+	assert.True(t, author.K("family").Undefined())
+
+	// values are copied out of the patch
+	j.K("phone").Raw().(map[string]interface{})["home"] = "2"
+	assert.Equal(t, "1", patch.K("phone").K("home").String().Value)
+}
+
+func TestMergePatchIntoReplaces(t *testing.T) {
+	j, err := NewJson(`{"a": {"b": 1}, "c": 2}`)
+	require.NoError(t, err)
+
+	patch, err := NewJson(`{"a": "scalar", "c": {"d": null, "e": 3}}`)
+	require.NoError(t, err)
+	require.NoError(t, j.MergePatchInto(patch))
+	assert.Equal(t, `{"a":"scalar","c":{"e":3}}`, j.Stringify())
+
+	patch, err = NewJson(`["whole"]`)
+	require.NoError(t, err)
+	require.NoError(t, j.MergePatchInto(patch))
+	assert.Equal(t, `["whole"]`, j.Stringify())
+
+	var undefined Json
+	patch, err = NewJson(`{"a": null, "b": 1}`)
+	require.NoError(t, err)
+	require.NoError(t, undefined.MergePatchInto(patch))
+	assert.Equal(t, `{"b":1}`, undefined.Stringify())
+
+	assert.EqualError(t, j.MergePatchInto(Json{}), "patch is undefined")
+}
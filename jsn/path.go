@@ -0,0 +1,155 @@
+package jsn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var pathKeyEscaper = strings.NewReplacer(`\`, `\\`, ".", `\.`, "[", `\[`)
+
+// pathSegment is a step of a dotted path: an object key, or an array index
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parsePath splits a dotted path like `a.b[0].c` into segments.
+// `\` escapes the next character, so `a\.b` is the single key "a.b"
+func parsePath(path string) ([]pathSegment, error) {
+	segments := []pathSegment{}
+	if path == "" {
+		return segments, nil
+	}
+
+	var key strings.Builder
+	hasKey := false       // the current part has key characters
+	afterBracket := false // the current part ended with an index
+
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+
+		if afterBracket && c != '.' && c != '[' {
+			return nil, fmt.Errorf("invalid path %q: unexpected %q after index", path, c)
+		}
+
+		switch c {
+		case '\\':
+			if i+1 == len(path) {
+				return nil, fmt.Errorf("invalid path %q: trailing backslash", path)
+			}
+			i++
+			key.WriteByte(path[i])
+			hasKey = true
+		case '.':
+			if !afterBracket {
+				segments = append(segments, pathSegment{key: key.String()})
+			}
+			key.Reset()
+			hasKey = false
+			afterBracket = false
+		case '[':
+			if hasKey {
+				segments = append(segments, pathSegment{key: key.String()})
+				key.Reset()
+				hasKey = false
+			}
+
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed bracket", path)
+			}
+			index, ok := arrayIndex(path[i+1 : i+end])
+			if !ok {
+				return nil, fmt.Errorf("invalid path %q: bad index %q", path, path[i+1:i+end])
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+			i += end
+			afterBracket = true
+		default:
+			key.WriteByte(c)
+			hasKey = true
+		}
+	}
+	if !afterBracket {
+		segments = append(segments, pathSegment{key: key.String()})
+	}
+
+	return segments, nil
+}
+
+// formatPath writes segments back as a dotted path, escaping keys as needed
+func formatPath(segments []pathSegment) string {
+	var b strings.Builder
+	for i, s := range segments {
+		if s.isIndex {
+			b.WriteString("[" + strconv.Itoa(s.index) + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(pathKeyEscaper.Replace(s.key))
+	}
+
+	return b.String()
+}
+
+func (j Json) getSegments(segments []pathSegment) Json {
+	v := j
+	for _, s := range segments {
+		if s.isIndex {
+			v = v.I(s.index)
+		} else {
+			v = v.Get(s.key)
+		}
+	}
+
+	return v
+}
+
+// GetPath returns the nested value at a dotted path, a shortcut for chaining Get and I:
+// j.GetPath("a.b[0].c") is j.K("a").K("b").I(0).K("c").
+// A backslash escapes the next character, so keys containing dots or brackets can be
+// reached: `a\.b` is the key "a.b". An empty path returns this Json.
+// returns an undefined Json{} as soon as a segment is missing or of the wrong type,
+// like Get and I do, and also if the path is malformed (e.g. an unclosed bracket).
+func (j Json) GetPath(path string) Json {
+	segments, err := parsePath(path)
+	if err != nil {
+		return Json{}
+	}
+
+	return j.getSegments(segments)
+}
+
+// Path is a dotted path compiled by CompilePath, to look up many documents
+// without parsing the expression every time
+type Path struct {
+	expr     string
+	segments []pathSegment
+}
+
+// CompilePath parses a dotted path expression, with the syntax of GetPath, for use with
+// GetCompiled. The expression is validated up front: an error is returned if it's malformed
+// (e.g. an unclosed bracket or a bad index), where GetPath would just return an undefined Json.
+func CompilePath(expr string) (Path, error) {
+	segments, err := parsePath(expr)
+	if err != nil {
+		return Path{}, err
+	}
+
+	return Path{expr: expr, segments: segments}, nil
+}
+
+// String returns the expression the path was compiled from
+func (p Path) String() string {
+	return p.expr
+}
+
+// GetCompiled returns the nested value at a compiled path, like GetPath does for its expression.
+// The zero Path returns this Json.
+func (j Json) GetCompiled(p Path) Json {
+	return j.getSegments(p.segments)
+}
@@ -0,0 +1,121 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePath(t *testing.T) {
+	segments, err := parsePath(`a.b[0][12].c\.d.e\[f\\`)
+	require.NoError(t, err)
+	assert.Equal(t, []pathSegment{
+		{key: "a"},
+		{key: "b"},
+		{index: 0, isIndex: true},
+		{index: 12, isIndex: true},
+		{key: "c.d"},
+		{key: `e[f\`},
+	}, segments)
+
+	segments, err = parsePath(`[1].x..y`)
+	require.NoError(t, err)
+	assert.Equal(t, []pathSegment{{index: 1, isIndex: true}, {key: "x"}, {key: ""}, {key: "y"}}, segments)
+
+	for _, bad := range []string{`a[0`, `a[x]`, `a[01]`, `a[]`, `a[0]b`, `a\`} {
+		_, err := parsePath(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestGetPath(t *testing.T) {
+	j, err := NewJson(`{
+		"a": {"b": [{"c": 1}, {"c": 2, "d": [[10, 11]]}]},
+		"dotted.key": {"x": "y"},
+		"list": ["zero"]
+	}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, j.GetPath("a.b[0].c").Int().Value)
+	assert.Equal(t, 11, j.GetPath("a.b[1].d[0][1]").Int().Value)
+	assert.Equal(t, "y", j.GetPath(`dotted\.key.x`).String().Value)
+	assert.Equal(t, "zero", j.GetPath("list[0]").String().Value)
+	assert.Equal(t, "zero", j.K("list").GetPath("[0]").String().Value)
+	assert.True(t, j.Equal(j.GetPath("")))
+
+	// missing segments
+	assert.True(t, j.GetPath("a.x.c").Undefined())
+	assert.True(t, j.GetPath("dotted.key.x").Undefined())
+	// out of bounds
+	assert.True(t, j.GetPath("a.b[2].c").Undefined())
+	assert.True(t, j.GetPath("list[1]").Undefined())
+	// wrong types
+	assert.True(t, j.GetPath("a[0]").Undefined())
+	assert.True(t, j.GetPath("a.b.c").Undefined())
+	assert.True(t, j.GetPath("list[0].x").Undefined())
+	// malformed
+	assert.True(t, j.GetPath("a.b[0").Undefined())
+}
+
+func TestCompilePath(t *testing.T) {
+	p, err := CompilePath(`users[1].name\.first`)
+	require.NoError(t, err)
+	assert.Equal(t, `users[1].name\.first`, p.String())
+
+	j, err := NewJson(`{"users": [{"name.first": "a"}, {"name.first": "b"}]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "b", j.GetCompiled(p).String().Value)
+	assert.Equal(t, j.GetPath(`users[1].name\.first`), j.GetCompiled(p))
+
+	other, err := NewJson(`{"users": [{"name": "c"}]}`)
+	require.NoError(t, err)
+	assert.True(t, other.GetCompiled(p).Undefined())
+
+	assert.True(t, j.Equal(j.GetCompiled(Path{})))
+
+	_, err = CompilePath("users[1")
+	assert.EqualError(t, err, `invalid path "users[1": unclosed bracket`)
+	_, err = CompilePath("users[-1]")
+	assert.EqualError(t, err, `invalid path "users[-1]": bad index "-1"`)
+	_, err = CompilePath("users[0]name")
+	assert.EqualError(t, err, `invalid path "users[0]name": unexpected 'n' after index`)
+}
+
+func benchmarkDocs(b *testing.B) []Json {
+	docs := make([]Json, 100)
+	for i := range docs {
+		j, err := NewJson(map[string]interface{}{
+			"user": map[string]interface{}{
+				"addresses": []interface{}{map[string]interface{}{"city": "x", "zip": i}},
+			},
+		})
+		require.NoError(b, err)
+		docs[i] = j
+	}
+	return docs
+}
+
+func BenchmarkGetPath(b *testing.B) {
+	docs := benchmarkDocs(b)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, d := range docs {
+			d.GetPath("user.addresses[0].zip")
+		}
+	}
+}
+
+func BenchmarkGetCompiled(b *testing.B) {
+	docs := benchmarkDocs(b)
+	p, err := CompilePath("user.addresses[0].zip")
+	require.NoError(b, err)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, d := range docs {
+			d.GetCompiled(p)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package jsn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// escapePointerToken escapes a key for use as a JSON Pointer (RFC 6901) token
+func escapePointerToken(key string) string {
+	return pointerEscaper.Replace(key)
+}
+
+// parsePointer splits a JSON Pointer into its unescaped reference tokens
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return []string{}, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with '/'", ptr)
+	}
+
+	tokens := strings.Split(ptr[1:], "/")
+	for i, t := range tokens {
+		for k := 0; k < len(t); k++ {
+			if t[k] == '~' && (k+1 == len(t) || (t[k+1] != '0' && t[k+1] != '1')) {
+				return nil, fmt.Errorf("invalid JSON pointer %q: bad escape in %q", ptr, t)
+			}
+		}
+		tokens[i] = pointerUnescaper.Replace(t)
+	}
+
+	return tokens, nil
+}
+
+// arrayIndex parses a JSON Pointer token as an array index.
+// leading zeros are not allowed, per RFC 6901
+func arrayIndex(token string) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+
+	i, err := strconv.Atoi(token)
+	return i, err == nil
+}
+
+// Pointer returns the value a JSON Pointer (RFC 6901) refers to, e.g. "/a/b/0/c".
+// In keys, "~1" stands for "/" and "~0" for "~". The empty pointer "" refers to the whole document.
+// Tokens into an array must be indexes, without leading zeros.
+// Defaults to an undefined Json for missing keys, out of range indexes, and malformed pointers.
+func (j Json) Pointer(ptr string) Json {
+	v, err := j.resolvePointer(ptr)
+	if err != nil {
+		return Json{}
+	}
+
+	return v
+}
+
+// resolvePointer returns the value a JSON Pointer refers to,
+// or an undefined Json if there is no such value.
+// an error is returned only if the pointer is malformed
+func (j Json) resolvePointer(ptr string) (Json, error) {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return Json{}, err
+	}
+
+	v := j
+	for _, t := range tokens {
+		if _, ok := v.asArray(); ok {
+			i, ok := arrayIndex(t)
+			if !ok {
+				return Json{}, nil
+			}
+			v = v.I(i)
+		} else {
+			v = v.Get(t)
+		}
+	}
+
+	return v, nil
+}
+
+// setPointer stores value at a JSON Pointer in data, and returns the updated data.
+// the parent of the location must exist; an array index may be one past the end,
+// or "-", to append. maps are modified in place
+func setPointer(data interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	t, last := tokens[0], len(tokens) == 1
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if last {
+			v[t] = value
+			return v, nil
+		}
+		child, exists := v[t]
+		if !exists {
+			return nil, fmt.Errorf("key %q not found", t)
+		}
+		e, err := setPointer(child, tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		v[t] = e
+		return v, nil
+	case []interface{}:
+		i, ok := arrayIndex(t)
+		if t == "-" {
+			i, ok = len(v), true
+		}
+		if !ok || i > len(v) || (i == len(v) && !last) {
+			return nil, fmt.Errorf("index %q out of bounds", t)
+		}
+		if i == len(v) {
+			return append(v, value), nil
+		}
+		if last {
+			v[i] = value
+			return v, nil
+		}
+		e, err := setPointer(v[i], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = e
+		return v, nil
+	default:
+		return nil, fmt.Errorf("can't set %q in %s", t, Json{data: data, exists: true}.Type())
+	}
+}
@@ -0,0 +1,80 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointer(t *testing.T) {
+	// the example of RFC 6901, section 5
+	j, err := NewJson(`{
+		"foo": ["bar", "baz"],
+		"": 0,
+		"a/b": 1,
+		"c%d": 2,
+		"e^f": 3,
+		"g|h": 4,
+		"i\\j": 5,
+		"k\"l": 6,
+		" ": 7,
+		"m~n": 8
+	}`)
+	require.NoError(t, err)
+
+	assert.True(t, j.Equal(j.Pointer("")))
+	assert.Equal(t, `["bar","baz"]`, j.Pointer("/foo").Stringify())
+	assert.Equal(t, "bar", j.Pointer("/foo/0").String().Value)
+
+	tests := map[string]int{
+		"/":     0,
+		"/a~1b": 1,
+		"/c%d":  2,
+		"/e^f":  3,
+		"/g|h":  4,
+		"/i\\j": 5,
+		"/k\"l": 6,
+		"/ ":    7,
+		"/m~0n": 8,
+	}
+	for ptr, expected := range tests {
+		v := j.Pointer(ptr)
+		assert.True(t, v.Int().IsValid, ptr)
+		assert.Equal(t, expected, v.Int().Value, ptr)
+	}
+}
+
+func TestPointerNested(t *testing.T) {
+	j, err := NewJson(`{"a": {"b": [{"c": "deep"}, null]}, "~1": "escaped"}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "deep", j.Pointer("/a/b/0/c").String().Value)
+	assert.True(t, j.Pointer("/a/b/1").Null())
+	assert.Equal(t, "escaped", j.Pointer("/~01").String().Value)
+}
+
+func TestPointerUndefined(t *testing.T) {
+	j, err := NewJson(`{"a": {"b": [1, 2]}, "s": "str"}`)
+	require.NoError(t, err)
+
+	for _, ptr := range []string{
+		"/missing",
+		"/a/missing",
+		"/a/b/2",
+		"/a/b/-1",
+		"/a/b/01",
+		"/a/b/-",
+		"/a/b/x",
+		"/s/0",
+		"/a/b/0/c",
+		"a",
+		"/bad~escape",
+		"/a~",
+	} {
+		assert.True(t, j.Pointer(ptr).Undefined(), ptr)
+	}
+
+	assert.True(t, Json{}.Pointer("").Undefined())
+	assert.True(t, Json{}.Pointer("/a").Undefined())
+}
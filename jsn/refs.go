@@ -0,0 +1,184 @@
+package jsn
+
+import (
+	"fmt"
+	"strings"
+)
+
+type inheritanceResolver struct {
+	root      Json
+	baseKey   string
+	resolving map[string]bool
+}
+
+// ResolveInheritance returns a copy of the document where every object holding baseKey
+// (e.g. "$extends") inherits the keys of the object that baseKey points to.
+// The baseKey value is a JSON Pointer into this document, optionally prefixed with "#".
+// The object's own keys win over inherited ones (nested objects are deep-merged),
+// and baseKey itself is removed. Inherited objects may themselves extend others.
+// An error is returned for inheritance cycles, or for a target that's missing or not an object.
+func (j Json) ResolveInheritance(baseKey string) (Json, error) {
+	if !j.exists {
+		return j, nil
+	}
+
+	r := inheritanceResolver{root: j, baseKey: baseKey, resolving: map[string]bool{}}
+	data, err := r.resolve("", j.data)
+	if err != nil {
+		return Json{}, err
+	}
+
+	return Json{data: data, exists: true}, nil
+}
+
+func (r *inheritanceResolver) resolve(pointer string, data interface{}) (interface{}, error) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if r.resolving[pointer] {
+			return nil, fmt.Errorf("%q: inheritance cycle", pointer)
+		}
+		r.resolving[pointer] = true
+		defer delete(r.resolving, pointer)
+
+		own := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			if k == r.baseKey {
+				continue
+			}
+			resolved, err := r.resolve(pointer+"/"+escapePointerToken(k), e)
+			if err != nil {
+				return nil, err
+			}
+			own[k] = resolved
+		}
+
+		ref, extends := v[r.baseKey]
+		if !extends {
+			return own, nil
+		}
+
+		base, err := r.resolveTarget(pointer, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		merged, _ := (&mergeConfig{}).merge(pointer, base, own)
+		return merged, nil
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, e := range v {
+			resolved, err := r.resolve(fmt.Sprintf("%s/%d", pointer, i), e)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = resolved
+		}
+		return a, nil
+	default:
+		return v, nil
+	}
+}
+
+func (r *inheritanceResolver) resolveTarget(pointer string, ref interface{}) (interface{}, error) {
+	target, ok := ref.(string)
+	if !ok {
+		return nil, fmt.Errorf("%q: %s must be a JSON pointer string", pointer, r.baseKey)
+	}
+	target = strings.TrimPrefix(target, "#")
+
+	v, err := r.root.resolvePointer(target)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %v", pointer, err)
+	}
+	if _, ok := v.asMap(); !ok {
+		return nil, fmt.Errorf("%q: %s target %q is not an object", pointer, r.baseKey, target)
+	}
+
+	return r.resolve(target, v.data)
+}
+
+type refResolver struct {
+	root      Json
+	resolving map[string]bool
+}
+
+// ResolveRefs returns a copy of the document where every JSON Reference object,
+// like {"$ref": "#/definitions/user"}, is replaced by the content of the value it refers to,
+// as found in OpenAPI and JSON Schema documents.
+// Only local references are supported: "#" followed by a JSON Pointer into this document
+// (used as is, without percent-decoding). Other keys of a reference object are ignored.
+// Referenced values may hold references themselves, which are resolved as well.
+// An error is returned for a reference cycle, i.e. a reference that, directly or through
+// other references, leads back to itself or to a value containing it, for non-local
+// references, and for references to missing values.
+func (j Json) ResolveRefs() (Json, error) {
+	if !j.exists {
+		return j, nil
+	}
+
+	r := refResolver{root: j, resolving: map[string]bool{}}
+	data, err := r.resolve("", j.data)
+	if err != nil {
+		return Json{}, err
+	}
+
+	return Json{data: data, exists: true}, nil
+}
+
+func (r *refResolver) resolve(pointer string, data interface{}) (interface{}, error) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if r.resolving[pointer] {
+			return nil, fmt.Errorf("%q: reference cycle", pointer)
+		}
+		r.resolving[pointer] = true
+		defer delete(r.resolving, pointer)
+
+		if ref, isRef := v["$ref"]; isRef {
+			return r.resolveRef(pointer, ref)
+		}
+
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			resolved, err := r.resolve(pointer+"/"+escapePointerToken(k), e)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = resolved
+		}
+		return m, nil
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, e := range v {
+			resolved, err := r.resolve(fmt.Sprintf("%s/%d", pointer, i), e)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = resolved
+		}
+		return a, nil
+	default:
+		return v, nil
+	}
+}
+
+func (r *refResolver) resolveRef(pointer string, ref interface{}) (interface{}, error) {
+	s, ok := ref.(string)
+	if !ok {
+		return nil, fmt.Errorf("%q: $ref must be a string", pointer)
+	}
+	if !strings.HasPrefix(s, "#") {
+		return nil, fmt.Errorf("%q: $ref %q is not a local reference", pointer, s)
+	}
+	target := strings.TrimPrefix(s, "#")
+
+	v, err := r.root.resolvePointer(target)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %v", pointer, err)
+	}
+	if !v.exists {
+		return nil, fmt.Errorf("%q: $ref target %q not found", pointer, s)
+	}
+
+	return r.resolve(target, v.data)
+}
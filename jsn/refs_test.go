@@ -0,0 +1,146 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveInheritance(t *testing.T) {
+	j, err := NewJson(`{
+		"base": {"host": "localhost", "port": 80, "tls": {"enabled": false, "version": 1.2}},
+		"prod": {"$extends": "/base", "host": "example.com", "tls": {"enabled": true}},
+		"canary": {"$extends": "#/prod", "port": 8080},
+		"list": [{"$extends": "/base", "port": 1}]
+	}`)
+	require.NoError(t, err)
+
+	resolved, err := j.ResolveInheritance("$extends")
+	require.NoError(t, err)
+
+	expected, err := NewJson(`{
+		"base": {"host": "localhost", "port": 80, "tls": {"enabled": false, "version": 1.2}},
+		"prod": {"host": "example.com", "port": 80, "tls": {"enabled": true, "version": 1.2}},
+		"canary": {"host": "example.com", "port": 8080, "tls": {"enabled": true, "version": 1.2}},
+		"list": [{"host": "localhost", "port": 1, "tls": {"enabled": false, "version": 1.2}}]
+	}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(resolved), resolved.Pretty())
+
+	assert.Equal(t, "/base", j.K("prod").K("$extends").String().Value, "receiver must not be modified")
+}
+
+func TestResolveInheritanceErrors(t *testing.T) {
+	j, err := NewJson(`{
+		"a": {"$extends": "/b", "x": 1},
+		"b": {"$extends": "/a", "y": 2}
+	}`)
+	require.NoError(t, err)
+	_, err = j.ResolveInheritance("$extends")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle")
+
+	j, err = NewJson(`{"a": {"$extends": "/a/b", "b": {"$extends": "/a"}}}`)
+	require.NoError(t, err)
+	_, err = j.ResolveInheritance("$extends")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle")
+
+	j, err = NewJson(`{"a": {"$extends": "/missing"}}`)
+	require.NoError(t, err)
+	_, err = j.ResolveInheritance("$extends")
+	assert.EqualError(t, err, `"/a": $extends target "/missing" is not an object`)
+
+	j, err = NewJson(`{"a": {"$extends": 1}}`)
+	require.NoError(t, err)
+	_, err = j.ResolveInheritance("$extends")
+	assert.EqualError(t, err, `"/a": $extends must be a JSON pointer string`)
+}
+
+func TestResolveRefs(t *testing.T) {
+	j, err := NewJson(`{
+		"definitions": {
+			"id": {"type": "integer"},
+			"user": {"type": "object", "properties": {"id": {"$ref": "#/definitions/id"}, "name": {"type": "string"}}},
+			"a~b": {"type": "null"}
+		},
+		"paths": [{"schema": {"$ref": "#/definitions/user", "description": "ignored"}}],
+		"escaped": {"$ref": "#/definitions/a~0b"},
+		"whole": {"wrapped": {"$ref": "#/definitions/id"}}
+	}`)
+	require.NoError(t, err)
+
+	resolved, err := j.ResolveRefs()
+	require.NoError(t, err)
+
+	expected, err := NewJson(`{
+		"definitions": {
+			"id": {"type": "integer"},
+			"user": {"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}},
+			"a~b": {"type": "null"}
+		},
+		"paths": [{"schema": {"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}}}],
+		"escaped": {"type": "null"},
+		"whole": {"wrapped": {"type": "integer"}}
+	}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(resolved), resolved.Pretty())
+
+	assert.Equal(t, "#/definitions/id", j.Pointer("/whole/wrapped/$ref").String().Value, "receiver must not be modified")
+
+	resolved.Pointer("/paths/0/schema/properties/id").Raw().(map[string]interface{})["type"] = "number"
+	assert.Equal(t, "integer", resolved.Pointer("/definitions/id/type").String().Value, "resolved copies are independent")
+}
+
+func TestResolveRefsRoot(t *testing.T) {
+	j, err := NewJson(`{"a": 1, "b": {"$ref": "#/a"}}`)
+	require.NoError(t, err)
+	resolved, err := j.ResolveRefs()
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1,"b":1}`, resolved.Stringify())
+
+	j, err = NewJson(`{"$ref": "#/x", "x": [1]}`)
+	require.NoError(t, err)
+	resolved, err = j.ResolveRefs()
+	require.NoError(t, err)
+	assert.Equal(t, `[1]`, resolved.Stringify())
+}
+
+func TestResolveRefsErrors(t *testing.T) {
+	j, err := NewJson(`{"a": {"$ref": "#/b"}, "b": {"$ref": "#/a"}}`)
+	require.NoError(t, err)
+	_, err = j.ResolveRefs()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "reference cycle")
+
+	j, err = NewJson(`{"node": {"children": [{"$ref": "#/node"}]}}`)
+	require.NoError(t, err)
+	_, err = j.ResolveRefs()
+	assert.EqualError(t, err, `"/node": reference cycle`)
+
+	j, err = NewJson(`{"self": {"$ref": "#"}}`)
+	require.NoError(t, err)
+	_, err = j.ResolveRefs()
+	assert.EqualError(t, err, `"": reference cycle`)
+
+	j, err = NewJson(`{"a": {"$ref": "#/missing"}}`)
+	require.NoError(t, err)
+	_, err = j.ResolveRefs()
+	assert.EqualError(t, err, `"/a": $ref target "#/missing" not found`)
+
+	j, err = NewJson(`{"a": {"$ref": "other.json#/a"}}`)
+	require.NoError(t, err)
+	_, err = j.ResolveRefs()
+	assert.EqualError(t, err, `"/a": $ref "other.json#/a" is not a local reference`)
+
+	j, err = NewJson(`{"a": {"$ref": 1}}`)
+	require.NoError(t, err)
+	_, err = j.ResolveRefs()
+	assert.EqualError(t, err, `"/a": $ref must be a string`)
+
+	j, err = NewJson(`{"a": {"$ref": "#a"}}`)
+	require.NoError(t, err)
+	_, err = j.ResolveRefs()
+	assert.Error(t, err)
+}
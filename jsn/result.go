@@ -0,0 +1,155 @@
+package jsn
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// PathError is the error returned by the Result accessors.
+// Path is the JSON Pointer of the full path that was requested, even when the lookup
+// failed before its end; Err tells why, and where the lookup stopped.
+type PathError struct {
+	Path string
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("%q: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// Result is a step of a checked lookup started with Json.At.
+// It chains like Get and I, but remembers the path it followed and the first failure,
+// so the typed accessors at the end of the chain (IntResult, StringResult...) return
+// an error naming the path instead of a silent zero value:
+//
+//	port, err := j.At("server").At("listeners").I(0).At("port").IntResult()
+//	// err: "/server/listeners/0/port": "/server/listeners" is missing
+type Result struct {
+	value Json
+	path  string
+	err   error
+}
+
+// At starts a checked lookup with the value under key (see Result)
+func (j Json) At(key string) Result {
+	return Result{value: j}.At(key)
+}
+
+// At continues the lookup with the value under key.
+// A missing key, or a value that isn't an object, makes the lookup fail.
+func (r Result) At(key string) Result {
+	return r.step(escapePointerToken(key), func() Json { return r.value.K(key) }, KindObject)
+}
+
+// I continues the lookup with the array element at index.
+// An out of bounds index, or a value that isn't an array, makes the lookup fail.
+func (r Result) I(index int) Result {
+	return r.step(strconv.Itoa(index), func() Json { return r.value.I(index) }, KindArray)
+}
+
+func (r Result) step(token string, child func() Json, container Kind) Result {
+	next := Result{path: r.path + "/" + token, err: r.err}
+	if r.err != nil {
+		return next
+	}
+
+	if kind := r.value.Type(); kind != container {
+		if kind == KindInvalid {
+			next.err = fmt.Errorf("%q is missing", r.path)
+		} else {
+			next.err = fmt.Errorf("%q is %s %s, not %s %s", r.path, article(kind), kind, article(container), container)
+		}
+		return next
+	}
+
+	next.value = child()
+	return next
+}
+
+func article(k Kind) string {
+	if k == KindArray || k == KindObject {
+		return "an"
+	}
+	return "a"
+}
+
+// Json returns the value found, or a *PathError if the lookup failed or the value is missing
+func (r Result) Json() (Json, error) {
+	if r.err != nil {
+		return Json{}, &PathError{Path: r.path, Err: r.err}
+	}
+	if r.value.Undefined() {
+		return Json{}, &PathError{Path: r.path, Err: errors.New("missing")}
+	}
+
+	return r.value, nil
+}
+
+// typed returns the error of a typed accessor, valid telling if the value converted
+func (r Result) typed(valid bool, expected Kind) error {
+	v, err := r.Json()
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return &PathError{Path: r.path, Err: fmt.Errorf("expected %s, got %s", expected, v.Type())}
+	}
+
+	return nil
+}
+
+// StringResult returns the string found, or a *PathError if the lookup failed,
+// or the value is missing or not a string
+func (r Result) StringResult() (string, error) {
+	v := r.value.String()
+	if err := r.typed(v.IsValid, KindString); err != nil {
+		return "", err
+	}
+	return v.Value, nil
+}
+
+// IntResult returns the number found as an int, or a *PathError if the lookup failed,
+// or the value is missing or not a number
+func (r Result) IntResult() (int, error) {
+	v := r.value.Int()
+	if err := r.typed(v.IsValid, KindNumber); err != nil {
+		return 0, err
+	}
+	return v.Value, nil
+}
+
+// Int64Result returns the number found as an int64, or a *PathError if the lookup failed,
+// or the value is missing or not a number
+func (r Result) Int64Result() (int64, error) {
+	v := r.value.Int64()
+	if err := r.typed(v.IsValid, KindNumber); err != nil {
+		return 0, err
+	}
+	return v.Value, nil
+}
+
+// Float64Result returns the number found as a float64, or a *PathError if the lookup failed,
+// or the value is missing or not a number
+func (r Result) Float64Result() (float64, error) {
+	v := r.value.Float64()
+	if err := r.typed(v.IsValid, KindNumber); err != nil {
+		return 0, err
+	}
+	return v.Value, nil
+}
+
+// BoolResult returns the bool found, or a *PathError if the lookup failed,
+// or the value is missing or not a bool
+func (r Result) BoolResult() (bool, error) {
+	v := r.value.Bool()
+	if err := r.typed(v.IsValid, KindBool); err != nil {
+		return false, err
+	}
+	return v.Value, nil
+}
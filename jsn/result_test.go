@@ -0,0 +1,70 @@
+package jsn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResult(t *testing.T) {
+	j, err := NewJson(`{"server": {"name": "api", "listeners": [{"port": 8080, "tls": true}], "ratio": 0.5}}`)
+	require.NoError(t, err)
+
+	port, err := j.At("server").At("listeners").I(0).At("port").IntResult()
+	require.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	port64, err := j.At("server").At("listeners").I(0).At("port").Int64Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(8080), port64)
+
+	tls, err := j.At("server").At("listeners").I(0).At("tls").BoolResult()
+	require.NoError(t, err)
+	assert.True(t, tls)
+
+	name, err := j.At("server").At("name").StringResult()
+	require.NoError(t, err)
+	assert.Equal(t, "api", name)
+
+	ratio, err := j.At("server").At("ratio").Float64Result()
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, ratio)
+
+	listener, err := j.At("server").At("listeners").I(0).Json()
+	require.NoError(t, err)
+	assert.True(t, listener.Exists("port"))
+}
+
+func TestResultErrors(t *testing.T) {
+	j, err := NewJson(`{"server": {"name": "api", "listeners": [{"port": "8080"}]}}`)
+	require.NoError(t, err)
+
+	_, err = j.At("server").At("http").At("listeners").I(0).At("port").IntResult()
+	assert.EqualError(t, err, `"/server/http/listeners/0/port": "/server/http" is missing`)
+	var pathErr *PathError
+	require.True(t, errors.As(err, &pathErr))
+	assert.Equal(t, "/server/http/listeners/0/port", pathErr.Path)
+
+	_, err = j.At("server").At("listeners").I(0).At("port").IntResult()
+	assert.EqualError(t, err, `"/server/listeners/0/port": expected number, got string`)
+
+	_, err = j.At("server").At("listeners").I(3).At("port").IntResult()
+	assert.EqualError(t, err, `"/server/listeners/3/port": "/server/listeners/3" is missing`)
+
+	_, err = j.At("server").At("name").At("first").StringResult()
+	assert.EqualError(t, err, `"/server/name/first": "/server/name" is a string, not an object`)
+
+	_, err = j.At("server").I(0).Json()
+	assert.EqualError(t, err, `"/server/0": "/server" is an object, not an array`)
+
+	_, err = j.At("server").At("port").Float64Result()
+	assert.EqualError(t, err, `"/server/port": missing`)
+
+	_, err = j.At("server").At("name").BoolResult()
+	assert.EqualError(t, err, `"/server/name": expected bool, got string`)
+
+	_, err = Json{}.At("a~b").StringResult()
+	assert.EqualError(t, err, `"/a~0b": "" is missing`)
+}
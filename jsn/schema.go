@@ -0,0 +1,64 @@
+package jsn
+
+// TrimToSchema returns a copy of the document keeping only what a JSON Schema declares,
+// as if every object schema had "additionalProperties": false, e.g. to make sure an API
+// response doesn't leak internal fields:
+//   - for an object value and a schema with "properties", only the declared keys are kept,
+//     each one trimmed recursively against its property schema
+//   - for an array value and a schema with "items", every element is trimmed against the
+//     "items" schema, or, if "items" is an array of schemas, element i against schema i
+//     (elements beyond them are kept as they are)
+//
+// A schema without "properties" (or "items", for arrays) doesn't constrain the value,
+// which is kept as a whole: {"type": "object"} keeps any object. Schemas that aren't
+// objects, like true, are unconstrained too. References and combinators ($ref, allOf,
+// anyOf...) aren't followed; resolve local references first with ResolveRefs.
+// Values of the wrong type for their schema are kept, as validating isn't the goal.
+// The receiver isn't modified.
+func (j Json) TrimToSchema(schema Json) Json {
+	if !j.exists {
+		return j
+	}
+
+	return Json{data: trimToSchema(j.data, schema.data), exists: true}
+}
+
+// trimToSchema returns a copy of data without what schema doesn't declare
+func trimToSchema(data, schema interface{}) interface{} {
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		return deepCopy(data)
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		properties, ok := s["properties"].(map[string]interface{})
+		if !ok {
+			return deepCopy(v)
+		}
+		m := make(map[string]interface{}, len(properties))
+		for k, e := range v {
+			if property, declared := properties[k]; declared {
+				m[k] = trimToSchema(e, property)
+			}
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, e := range v {
+			switch items := s["items"].(type) {
+			case []interface{}:
+				if i < len(items) {
+					a[i] = trimToSchema(e, items[i])
+				} else {
+					a[i] = deepCopy(e)
+				}
+			default:
+				a[i] = trimToSchema(e, items)
+			}
+		}
+		return a
+	default:
+		return v
+	}
+}
@@ -0,0 +1,71 @@
+package jsn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrimToSchema(t *testing.T) {
+	schema, err := NewJson(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"},
+			"profile": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "email": {"type": "string"}}
+			},
+			"roles": {
+				"type": "array",
+				"items": {"type": "object", "properties": {"name": {"type": "string"}}}
+			},
+			"settings": {"type": "object"},
+			"pair": {"type": "array", "items": [{"properties": {"a": {}}}, true]}
+		}
+	}`)
+	require.NoError(t, err)
+
+	doc, err := NewJson(`{
+		"id": 7,
+		"passwordHash": "secret",
+		"profile": {"name": "gopher", "email": "g@example.com", "internalNotes": "vip"},
+		"roles": [{"name": "admin", "grantedBy": 1}, {"name": "dev"}, "legacy"],
+		"settings": {"theme": "dark", "nested": {"anything": true}},
+		"pair": [{"a": 1, "b": 2}, {"c": 3}, {"d": 4}]
+	}`)
+	require.NoError(t, err)
+
+	trimmed := doc.TrimToSchema(schema)
+	expected, err := NewJson(`{
+		"id": 7,
+		"profile": {"name": "gopher", "email": "g@example.com"},
+		"roles": [{"name": "admin"}, {"name": "dev"}, "legacy"],
+		"settings": {"theme": "dark", "nested": {"anything": true}},
+		"pair": [{"a": 1}, {"c": 3}, {"d": 4}]
+	}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(trimmed), trimmed.Pretty())
+
+	assert.Equal(t, "secret", doc.K("passwordHash").String().Value, "receiver must not be modified")
+	trimmed.K("settings").Raw().(map[string]interface{})["theme"] = "light"
+	assert.Equal(t, "dark", doc.K("settings").K("theme").String().Value, "the result is a copy")
+}
+
+func TestTrimToSchemaUnconstrained(t *testing.T) {
+	doc, err := NewJson(`{"a": 1, "b": [1, {"c": 2}]}`)
+	require.NoError(t, err)
+
+	for _, src := range []string{`{}`, `{"type": "object"}`, `true`, `{"properties": "bad"}`} {
+		schema, err := NewJson(src)
+		require.NoError(t, err)
+		assert.True(t, doc.Equal(doc.TrimToSchema(schema)), src)
+	}
+	assert.True(t, doc.Equal(doc.TrimToSchema(Json{})))
+
+	schema, err := NewJson(`{"properties": {}}`)
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, doc.TrimToSchema(schema).Stringify())
+	assert.Equal(t, `"str"`, Json{data: "str", exists: true}.TrimToSchema(schema).Stringify())
+	assert.True(t, Json{}.TrimToSchema(schema).Undefined())
+}
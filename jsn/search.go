@@ -0,0 +1,27 @@
+package jsn
+
+import "regexp"
+
+// MatchesRegex returns whether this is a string value matching pattern.
+// Returns false for anything that isn't a string (numbers aren't converted).
+func (j Json) MatchesRegex(pattern *regexp.Regexp) bool {
+	s, ok := j.data.(string)
+	return ok && j.exists && pattern.MatchString(s)
+}
+
+// FindStringsMatching walks the whole document and returns the JSON Pointers of all
+// string values matching pattern, in depth-first traversal order (object keys sorted).
+// Only string leaves are considered: object keys, numbers, bools and nulls are ignored.
+// Returns an empty slice if nothing matches.
+func (j Json) FindStringsMatching(pattern *regexp.Regexp) []string {
+	pointers := []string{}
+
+	j.forEachLeaf(func(pointer string, v Json) bool {
+		if v.MatchesRegex(pattern) {
+			pointers = append(pointers, pointer)
+		}
+		return true
+	})
+
+	return pointers
+}
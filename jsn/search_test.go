@@ -0,0 +1,37 @@
+package jsn
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchesRegex(t *testing.T) {
+	j, err := NewJson(`{"id": "user-42", "n": 42, "tags": ["x"]}`)
+	require.NoError(t, err)
+
+	digits := regexp.MustCompile(`\d+`)
+	assert.True(t, j.K("id").MatchesRegex(digits))
+	assert.False(t, j.K("n").MatchesRegex(digits))
+	assert.False(t, j.K("tags").MatchesRegex(regexp.MustCompile(`x`)))
+	assert.False(t, j.K("missing").MatchesRegex(regexp.MustCompile(``)))
+}
+
+func TestFindStringsMatching(t *testing.T) {
+	j, err := NewJson(`{
+		"service": "api",
+		"db": {"user": "admin", "password": "AKIA1234SECRET", "port": 5432},
+		"keys": ["public", "AKIA9999OTHER", {"nested": "AKIA0000DEEP"}],
+		"AKIA_in_key": true,
+		"a/b": "AKIA1111SLASH"
+	}`)
+	require.NoError(t, err)
+
+	pointers := j.FindStringsMatching(regexp.MustCompile(`^AKIA[0-9]{4}`))
+	assert.Equal(t, []string{"/a~1b", "/db/password", "/keys/1", "/keys/2/nested"}, pointers)
+
+	assert.Equal(t, []string{}, j.FindStringsMatching(regexp.MustCompile(`5432`)))
+	assert.Equal(t, []string{""}, j.K("service").FindStringsMatching(regexp.MustCompile(`api`)))
+}
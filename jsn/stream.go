@@ -0,0 +1,51 @@
+package jsn
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Stream decodes a sequence of JSON values from a reader, one at a time,
+// e.g. the lines of a newline-delimited JSON (JSONL) log file
+type Stream struct {
+	dec *json.Decoder
+}
+
+// NewStream returns a Stream decoding values from r.
+// Values may be separated by any JSON whitespace, so JSONL is just a special case.
+// Like with NewJson, numbers are kept as json.Number.
+func NewStream(r io.Reader) *Stream {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	return &Stream{dec: dec}
+}
+
+// Next decodes the next value of the stream.
+// returns the value and true, or an undefined Json and false once the stream is exhausted.
+// A syntax or read error is returned with false, and the stream can't be resumed after it.
+func (s *Stream) Next() (Json, bool, error) {
+	var data interface{}
+	if err := s.dec.Decode(&data); err != nil {
+		if err == io.EOF {
+			return Json{}, false, nil
+		}
+		return Json{}, false, err
+	}
+
+	return Json{data: data, exists: true}, true, nil
+}
+
+// ForEach calls f for every remaining value of the stream, in order.
+// It stops at the first error, whether it's returned by f or by decoding, and returns it.
+func (s *Stream) ForEach(f func(Json) error) error {
+	for {
+		j, ok, err := s.Next()
+		if err != nil || !ok {
+			return err
+		}
+		if err := f(j); err != nil {
+			return err
+		}
+	}
+}
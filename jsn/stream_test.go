@@ -0,0 +1,89 @@
+package jsn
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream(t *testing.T) {
+	s := NewStream(strings.NewReader(`{"level": "info", "id": 12345678901234567}
+{"level": "warn"}
+
+[1, 2] "str" null
+`))
+
+	j, ok, err := s.Next()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "info", j.K("level").String().Value)
+	assert.Equal(t, json.Number("12345678901234567"), j.K("id").Raw())
+
+	var rest []string
+	for {
+		j, ok, err = s.Next()
+		require.NoError(t, err)
+		if !ok {
+			break
+		}
+		rest = append(rest, j.Stringify())
+	}
+	assert.Equal(t, []string{`{"level":"warn"}`, `[1,2]`, `"str"`, `null`}, rest)
+	assert.True(t, j.Undefined())
+
+	j, ok, err = s.Next()
+	assert.NoError(t, err)
+	assert.False(t, ok, "stays exhausted")
+}
+
+func TestStreamError(t *testing.T) {
+	s := NewStream(strings.NewReader("{\"a\": 1}\n{\"a\": \n"))
+
+	_, ok, err := s.Next()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	j, ok, err := s.Next()
+	assert.Error(t, err)
+	assert.False(t, ok)
+	assert.True(t, j.Undefined())
+}
+
+func TestStreamForEach(t *testing.T) {
+	var levels []string
+	err := NewStream(strings.NewReader("{\"level\": \"info\"}\n{\"level\": \"warn\"}\n")).ForEach(func(j Json) error {
+		levels = append(levels, j.K("level").String().Value)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"info", "warn"}, levels)
+
+	stop := errors.New("stop")
+	count := 0
+	err = NewStream(strings.NewReader("1 2 3")).ForEach(func(j Json) error {
+		count++
+		if j.Int().Value == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 2, count)
+
+	count = 0
+	err = NewStream(strings.NewReader("1 x")).ForEach(func(j Json) error {
+		count++
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, count)
+
+	assert.NoError(t, NewStream(strings.NewReader("")).ForEach(func(j Json) error {
+		assert.True(t, false, "should not be executed")
+		return nil
+	}))
+}
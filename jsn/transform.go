@@ -0,0 +1,101 @@
+package jsn
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Transform returns a copy of the document where f may rewrite any node.
+// f is called for every node, depth-first with object keys in sorted order (parents first),
+// with the node's JSON Pointer. To replace the node, f returns the new value and true:
+// the replacement is used as is, without visiting it. Returning an undefined Json with true
+// removes the node from its object or array. Returning false keeps the node, and the walk
+// goes on with its children.
+// The receiver isn't modified.
+func (j Json) Transform(f func(pointer string, v Json) (Json, bool)) Json {
+	transformed, _ := j.WalkTransformErr(func(pointer string, v Json) (Json, bool, error) {
+		replacement, replace := f(pointer, v)
+		return replacement, replace, nil
+	})
+
+	return transformed
+}
+
+// WalkTransformErr is like Transform, for rewrites that can fail: when f returns an error,
+// the whole transform is aborted, returning an undefined Json and the error prefixed with
+// the pointer of the failed node.
+// The transform works on a copy, so the receiver is left intact whether it fails or not.
+func (j Json) WalkTransformErr(f func(pointer string, v Json) (Json, bool, error)) (Json, error) {
+	if !j.exists {
+		return j, nil
+	}
+
+	data, keep, err := transformValue("", j.data, f)
+	if err != nil || !keep {
+		return Json{}, err
+	}
+
+	return Json{data: data, exists: true}, nil
+}
+
+// transformValue returns the transformed copy of data, and false if it was removed
+func transformValue(pointer string, data interface{}, f func(pointer string, v Json) (Json, bool, error)) (interface{}, bool, error) {
+	replacement, replace, err := f(pointer, Json{data: data, exists: true})
+	if err != nil {
+		return nil, false, fmt.Errorf("%q: %v", pointer, err)
+	}
+	if replace {
+		return deepCopy(replacement.data), replacement.exists, nil
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for _, k := range sortedKeys(v) {
+			e, keep, err := transformValue(pointer+"/"+escapePointerToken(k), v[k], f)
+			if err != nil {
+				return nil, false, err
+			}
+			if keep {
+				m[k] = e
+			}
+		}
+		return m, true, nil
+	case []interface{}:
+		a := make([]interface{}, 0, len(v))
+		for i, e := range v {
+			e, keep, err := transformValue(pointer+"/"+strconv.Itoa(i), e, f)
+			if err != nil {
+				return nil, false, err
+			}
+			if keep {
+				a = append(a, e)
+			}
+		}
+		return a, true, nil
+	default:
+		return v, true, nil
+	}
+}
+
+// RetainKinds returns a copy of the document keeping only the values of the given kinds:
+// every object value and array element of another Kind is removed, recursively.
+// A nested object or array is removed as a whole unless its Kind is allowed, in which case
+// its own content is filtered; one that ends up empty is kept as {} or [].
+// The receiver itself is always kept (and filtered) if it's an object or an array, so
+// RetainKinds(KindString, KindNumber) on an object gives a flat object of strings and numbers.
+// A scalar receiver of another Kind gives an undefined Json.
+func (j Json) RetainKinds(kinds ...Kind) Json {
+	allowed := map[Kind]bool{}
+	for _, k := range kinds {
+		allowed[k] = true
+	}
+
+	return j.Transform(func(pointer string, v Json) (Json, bool) {
+		kind := v.Type()
+		if allowed[kind] || (pointer == "" && (kind == KindObject || kind == KindArray)) {
+			return Json{}, false
+		}
+		return Json{}, true
+	})
+}
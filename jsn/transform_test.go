@@ -0,0 +1,84 @@
+package jsn
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform(t *testing.T) {
+	j, err := NewJson(`{"user": {"name": "alice", "password": "x", "emails": ["A@X.COM", "b@y.com"]}, "secret": {"a": 1}}`)
+	require.NoError(t, err)
+
+	var visited []string
+	transformed := j.Transform(func(pointer string, v Json) (Json, bool) {
+		visited = append(visited, pointer)
+		switch {
+		case pointer == "/secret":
+			return Json{data: "***", exists: true}, true
+		case strings.HasSuffix(pointer, "/password"):
+			return Json{}, true
+		case v.String().IsValid:
+			return Json{data: strings.ToLower(v.String().Value), exists: true}, true
+		}
+		return Json{}, false
+	})
+
+	expected, err := NewJson(`{"user": {"name": "alice", "emails": ["a@x.com", "b@y.com"]}, "secret": "***"}`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(transformed), transformed.Stringify())
+	assert.Equal(t, []string{"", "/secret", "/user", "/user/emails", "/user/emails/0", "/user/emails/1", "/user/name", "/user/password"}, visited)
+
+	assert.Equal(t, "x", j.K("user").K("password").String().Value, "receiver must not be modified")
+	assert.Equal(t, "A@X.COM", j.K("user").K("emails").I(0).String().Value)
+
+	removed := j.Transform(func(pointer string, v Json) (Json, bool) { return Json{}, true })
+	assert.True(t, removed.Undefined())
+}
+
+func TestWalkTransformErr(t *testing.T) {
+	j, err := NewJson(`{"ports": [80, 443, 70000], "name": "svc"}`)
+	require.NoError(t, err)
+
+	check := func(pointer string, v Json) (Json, bool, error) {
+		if f := v.Float64(); f.IsValid && f.Value > 65535 {
+			return Json{}, false, errors.New("port out of range")
+		}
+		if f := v.Float64(); f.IsValid {
+			return Json{data: f.Value + 1, exists: true}, true, nil
+		}
+		return Json{}, false, nil
+	}
+
+	transformed, err := j.WalkTransformErr(check)
+	assert.EqualError(t, err, `"/ports/2": port out of range`)
+	assert.True(t, transformed.Undefined())
+	assert.Equal(t, 80, j.K("ports").I(0).Int().Value, "receiver must not be modified")
+
+	transformed, err = j.K("ports").I(1).WalkTransformErr(check)
+	require.NoError(t, err)
+	assert.Equal(t, 444, transformed.Int().Value)
+
+	transformed, err = j.K("missing").WalkTransformErr(check)
+	require.NoError(t, err)
+	assert.True(t, transformed.Undefined())
+}
+
+func TestRetainKinds(t *testing.T) {
+	j, err := NewJson(`{"name": "svc", "port": 80, "debug": true, "none": null, "tags": ["a", 1, false], "db": {"host": "h", "opts": {}}}`)
+	require.NoError(t, err)
+
+	flat := j.RetainKinds(KindString, KindNumber)
+	assert.Equal(t, `{"name":"svc","port":80}`, flat.Stringify())
+
+	nested := j.RetainKinds(KindString, KindNumber, KindArray, KindObject)
+	assert.Equal(t, `{"db":{"host":"h","opts":{}},"name":"svc","port":80,"tags":["a",1]}`, nested.Stringify())
+
+	assert.Equal(t, `[]`, j.K("tags").RetainKinds(KindNull).Stringify())
+	assert.Equal(t, `"svc"`, j.K("name").RetainKinds(KindString).Stringify())
+	assert.True(t, j.K("name").RetainKinds(KindNumber).Undefined())
+	assert.True(t, j.Exists("debug"), "receiver must not be modified")
+}
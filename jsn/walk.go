@@ -0,0 +1,272 @@
+package jsn
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// walk calls f for this Json and every nested value, depth-first, passing
+// the JSON Pointer of the value and its depth (the receiver being at depth 0).
+// Object keys are visited in sorted order.
+// The walk stops as soon as f returns false.
+func (j Json) walk(f func(pointer string, depth int, v Json) bool) {
+	if !j.exists {
+		return
+	}
+
+	walkValue("", 0, j.data, f)
+}
+
+func walkValue(pointer string, depth int, data interface{}, f func(pointer string, depth int, v Json) bool) bool {
+	if !f(pointer, depth, Json{data: data, exists: true}) {
+		return false
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			if !walkValue(pointer+"/"+escapePointerToken(k), depth+1, v[k], f) {
+				return false
+			}
+		}
+	case []interface{}:
+		for i, e := range v {
+			if !walkValue(pointer+"/"+strconv.Itoa(i), depth+1, e, f) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// KeyFrequency walks the whole document and returns, for every key name,
+// the number of objects that contain it.
+// It counts object membership occurrences, not values: a key is counted once
+// per object it appears in, whatever its value is.
+// Summing the results of several documents shows which fields are common and which are rare.
+func (j Json) KeyFrequency() map[string]int {
+	freq := map[string]int{}
+
+	j.walk(func(pointer string, depth int, v Json) bool {
+		if m, ok := v.asMap(); ok {
+			for k := range m {
+				freq[k]++
+			}
+		}
+		return true
+	})
+
+	return freq
+}
+
+// NodeCounts walks the whole document and returns the number of scalar leaves
+// (strings, numbers, bools and nulls), of objects and of arrays it contains,
+// the receiver included. Empty objects and arrays are counted as containers, not leaves.
+// Returns zeros for an undefined Json.
+func (j Json) NodeCounts() (leaves int, objects int, arrays int) {
+	j.walk(func(pointer string, depth int, v Json) bool {
+		switch v.data.(type) {
+		case map[string]interface{}:
+			objects++
+		case []interface{}:
+			arrays++
+		default:
+			leaves++
+		}
+		return true
+	})
+
+	return leaves, objects, arrays
+}
+
+// NodesAtDepth walks the whole document and returns a histogram of its shape:
+// index d holds the number of values at depth d, the receiver being at depth 0,
+// the members of an object or the elements of an array one level deeper than it.
+// Every value is counted, containers included, so the sum is the total number of nodes
+// and the length is one more than the depth of the deepest value.
+// Returns an empty slice for an undefined Json.
+func (j Json) NodesAtDepth() []int {
+	counts := []int{}
+
+	j.walk(func(pointer string, depth int, v Json) bool {
+		if depth == len(counts) {
+			counts = append(counts, 0)
+		}
+		counts[depth]++
+		return true
+	})
+
+	return counts
+}
+
+// forEachLeaf calls f for every scalar (string, number, bool or null) value
+// in the document, in the same depth-first order as walk.
+// Empty objects and arrays are not leaves.
+func (j Json) forEachLeaf(f func(pointer string, v Json) bool) {
+	j.walk(func(pointer string, depth int, v Json) bool {
+		switch v.data.(type) {
+		case map[string]interface{}, []interface{}:
+			return true
+		default:
+			return f(pointer, v)
+		}
+	})
+}
+
+// AllNumbers returns every number in the document, whatever its location,
+// in depth-first traversal order (object keys sorted).
+// Returns an empty slice if there are none.
+func (j Json) AllNumbers() []float64 {
+	numbers := []float64{}
+
+	j.forEachLeaf(func(pointer string, v Json) bool {
+		if f := v.Float64(); f.IsValid {
+			numbers = append(numbers, f.Value)
+		}
+		return true
+	})
+
+	return numbers
+}
+
+// AllStrings returns every string value in the document (keys not included),
+// in depth-first traversal order (object keys sorted).
+// Returns an empty slice if there are none.
+func (j Json) AllStrings() []string {
+	strs := []string{}
+
+	j.forEachLeaf(func(pointer string, v Json) bool {
+		if s := v.String(); s.IsValid {
+			strs = append(strs, s.Value)
+		}
+		return true
+	})
+
+	return strs
+}
+
+// WalkParallel calls f for this Json and every nested value (objects and arrays included),
+// spreading the calls over a pool of workers goroutines (at least 1).
+// Every node is visited exactly once, in no particular order, and WalkParallel returns
+// when all calls are done.
+// Reading the document concurrently is safe as long as nobody modifies it,
+// but f must synchronize access to any state it shares between calls.
+func (j Json) WalkParallel(workers int, f func(pointer string, v Json)) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	type node struct {
+		pointer string
+		v       Json
+	}
+	nodes := make(chan node, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for n := range nodes {
+				f(n.pointer, n.v)
+			}
+		}()
+	}
+
+	j.walk(func(pointer string, depth int, v Json) bool {
+		nodes <- node{pointer, v}
+		return true
+	})
+	close(nodes)
+
+	wg.Wait()
+}
+
+// NodeContext describes a node visited by DeepForEach, along with its place in the document
+type NodeContext struct {
+	// Node is the visited value
+	Node Json
+	// Parent is the object or array holding Node (undefined for the root)
+	Parent Json
+	// Key is the key of Node in Parent when Parent is an object, "" otherwise
+	Key string
+	// Index is the index of Node in Parent when Parent is an array, -1 otherwise
+	Index int
+	// Pointer is the JSON Pointer of Node ("" for the root)
+	Pointer string
+	// Depth is the nesting level of Node (0 for the root)
+	Depth int
+}
+
+// Sibling returns the value of key in the object holding this node (key may be Node's own key).
+// Returns an undefined Json if the parent isn't an object (an array, or no parent for the root).
+func (c NodeContext) Sibling(key string) Json {
+	if _, ok := c.Parent.asMap(); !ok {
+		return Json{}
+	}
+
+	return c.Parent.K(key)
+}
+
+// DeepForEach calls f for this Json and every nested value, depth-first with object keys
+// in sorted order, like the plain walk, but passes each node with its context:
+// its parent, its key (for object members) or index (for array elements), and its pointer,
+// so rules can depend on neighboring fields through Sibling.
+// The walk stops as soon as f returns false.
+func (j Json) DeepForEach(f func(ctx NodeContext) bool) {
+	if !j.exists {
+		return
+	}
+
+	deepForEach(NodeContext{Node: j, Index: -1}, f)
+}
+
+func deepForEach(ctx NodeContext, f func(ctx NodeContext) bool) bool {
+	if !f(ctx) {
+		return false
+	}
+
+	switch v := ctx.Node.data.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			child := NodeContext{
+				Node:    Json{data: v[k], exists: true},
+				Parent:  ctx.Node,
+				Key:     k,
+				Index:   -1,
+				Pointer: ctx.Pointer + "/" + escapePointerToken(k),
+				Depth:   ctx.Depth + 1,
+			}
+			if !deepForEach(child, f) {
+				return false
+			}
+		}
+	case []interface{}:
+		for i, e := range v {
+			child := NodeContext{
+				Node:    Json{data: e, exists: true},
+				Parent:  ctx.Node,
+				Index:   i,
+				Pointer: ctx.Pointer + "/" + strconv.Itoa(i),
+				Depth:   ctx.Depth + 1,
+			}
+			if !deepForEach(child, f) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
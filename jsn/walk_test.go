@@ -0,0 +1,213 @@
+package jsn
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkOrder(t *testing.T) {
+	j, err := NewJson(`{"b": [1, {"c": null}], "a": {"x/y": true, "t~": 2}}`)
+	require.NoError(t, err)
+
+	var pointers []string
+	var depths []int
+	j.walk(func(pointer string, depth int, v Json) bool {
+		pointers = append(pointers, pointer)
+		depths = append(depths, depth)
+		return true
+	})
+
+	assert.Equal(t, []string{"", "/a", "/a/t~0", "/a/x~1y", "/b", "/b/0", "/b/1", "/b/1/c"}, pointers)
+	assert.Equal(t, []int{0, 1, 2, 2, 1, 2, 2, 3}, depths)
+
+	count := 0
+	j.walk(func(pointer string, depth int, v Json) bool {
+		count++
+		return pointer != "/a"
+	})
+	assert.Equal(t, 2, count)
+
+	Json{}.walk(func(pointer string, depth int, v Json) bool {
+		assert.True(t, false, "should not be executed")
+		return true
+	})
+}
+
+func TestKeyFrequency(t *testing.T) {
+	j, err := NewJson(`{
+		"id": 1,
+		"name": "root",
+		"children": [
+			{"id": 2, "name": "a"},
+			{"id": 3, "tags": ["x"]},
+			{"id": 4, "meta": {"name": "deep"}}
+		]
+	}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{
+		"id":       4,
+		"name":     3,
+		"children": 1,
+		"tags":     1,
+		"meta":     1,
+	}, j.KeyFrequency())
+
+	assert.Equal(t, map[string]int{}, j.K("no").KeyFrequency())
+	assert.Equal(t, map[string]int{}, j.K("id").KeyFrequency())
+}
+
+func TestNodeCounts(t *testing.T) {
+	j, err := NewJson(`{"a": 1, "b": null, "c": [true, "x", {}, []], "d": {"e": {"f": 2.5}}}`)
+	require.NoError(t, err)
+
+	leaves, objects, arrays := j.NodeCounts()
+	assert.Equal(t, 5, leaves)
+	assert.Equal(t, 4, objects)
+	assert.Equal(t, 2, arrays)
+
+	leaves, objects, arrays = j.K("a").NodeCounts()
+	assert.Equal(t, []int{1, 0, 0}, []int{leaves, objects, arrays})
+
+	leaves, objects, arrays = j.K("no").NodeCounts()
+	assert.Equal(t, []int{0, 0, 0}, []int{leaves, objects, arrays})
+}
+
+func TestNodesAtDepth(t *testing.T) {
+	j, err := NewJson(`{"a": 1, "b": [1, 2, {"c": [3]}], "d": {}, "e": {"f": null}}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 4, 4, 1, 1}, j.NodesAtDepth())
+	assert.Equal(t, []int{1, 3, 1, 1}, j.K("b").NodesAtDepth())
+	assert.Equal(t, []int{1}, j.K("a").NodesAtDepth())
+	assert.Equal(t, []int{1}, j.K("d").NodesAtDepth())
+	assert.Equal(t, []int{}, j.K("missing").NodesAtDepth())
+}
+
+func TestAllNumbersAndStrings(t *testing.T) {
+	j, err := NewJson(`{
+		"b": ["one", 2, {"c": 3.5, "d": "four"}],
+		"a": {"x": 1, "y": "zero", "z": [true, null]},
+		"e": {}
+	}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{1, 2, 3.5}, j.AllNumbers())
+	assert.Equal(t, []string{"zero", "one", "four"}, j.AllStrings())
+
+	assert.Equal(t, []float64{}, j.K("a").K("z").AllNumbers())
+	assert.Equal(t, []string{}, j.K("no").AllStrings())
+
+	s, err := NewJson(`"lonely"`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lonely"}, s.AllStrings())
+}
+
+func TestWalkParallel(t *testing.T) {
+	j, err := NewJson(`{"a": [1, 2, {"b": null}], "c": {"d": "e", "f": []}}`)
+	require.NoError(t, err)
+
+	var expected []string
+	j.walk(func(pointer string, depth int, v Json) bool {
+		expected = append(expected, pointer)
+		return true
+	})
+
+	for _, workers := range []int{0, 1, 4, 100} {
+		var mu sync.Mutex
+		visits := map[string]int{}
+		j.WalkParallel(workers, func(pointer string, v Json) {
+			mu.Lock()
+			defer mu.Unlock()
+			visits[pointer]++
+		})
+
+		assert.Len(t, visits, len(expected))
+		for _, p := range expected {
+			assert.Equal(t, 1, visits[p], "pointer %q with %d workers", p, workers)
+		}
+	}
+
+	Json{}.WalkParallel(2, func(pointer string, v Json) {
+		assert.True(t, false, "should not be executed")
+	})
+}
+
+func benchmarkWalkParallel(b *testing.B, workers int) {
+	items := make([]string, 500)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id": %d, "name": "item-%d", "tags": ["a", "b"]}`, i, i)
+	}
+	j, err := NewJson("[" + strings.Join(items, ",") + "]")
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		j.WalkParallel(workers, func(pointer string, v Json) {
+			// simulate expensive per-node work
+			sum := sha256.Sum256([]byte(pointer))
+			for k := 0; k < 200; k++ {
+				sum = sha256.Sum256(sum[:])
+			}
+		})
+	}
+}
+
+func BenchmarkWalkParallel1(b *testing.B) { benchmarkWalkParallel(b, 1) }
+func BenchmarkWalkParallel4(b *testing.B) { benchmarkWalkParallel(b, 4) }
+
+func TestDeepForEach(t *testing.T) {
+	j, err := NewJson(`{
+		"fields": [
+			{"type": "text", "name": "user", "value": "alice"},
+			{"type": "secret", "name": "token", "value": "s3cr3t"},
+			{"type": "secret", "name": "empty"}
+		],
+		"value": "top"
+	}`)
+	require.NoError(t, err)
+
+	var redacted []string
+	j.DeepForEach(func(ctx NodeContext) bool {
+		if ctx.Key == "value" && ctx.Sibling("type").String().Value == "secret" {
+			// Parent shares the document's data, so setting a key on it redacts in place
+			require.NoError(t, ctx.Parent.Set(ctx.Key, "***"))
+			redacted = append(redacted, ctx.Pointer)
+		}
+		return true
+	})
+
+	assert.Equal(t, []string{"/fields/1/value"}, redacted)
+	assert.Equal(t, "alice", j.K("fields").I(0).K("value").String().Value)
+	assert.Equal(t, "***", j.K("fields").I(1).K("value").String().Value)
+	assert.Equal(t, "top", j.K("value").String().Value)
+
+	var contexts []NodeContext
+	j.DeepForEach(func(ctx NodeContext) bool {
+		contexts = append(contexts, ctx)
+		return ctx.Pointer != "/fields/0"
+	})
+	require.Len(t, contexts, 3)
+
+	root, fields, elem := contexts[0], contexts[1], contexts[2]
+	assert.True(t, root.Parent.Undefined())
+	assert.Equal(t, -1, root.Index)
+	assert.Equal(t, 0, root.Depth)
+	assert.True(t, root.Sibling("value").Undefined())
+
+	assert.Equal(t, "fields", fields.Key)
+	assert.Equal(t, -1, fields.Index)
+	assert.Equal(t, "top", fields.Sibling("value").String().Value)
+
+	assert.Equal(t, "", elem.Key)
+	assert.Equal(t, 0, elem.Index)
+	assert.Equal(t, "/fields/0", elem.Pointer)
+	assert.Equal(t, 2, elem.Depth)
+	assert.True(t, elem.Sibling("type").Undefined(), "array parents have no siblings by key")
+}